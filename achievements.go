@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Achievement struct {
+	ID     string
+	Name   string
+	Desc   string
+	Event  EventKind
+	Target string
+	Count  int
+}
+
+func Achievements() []Achievement {
+	return []Achievement{
+		{ID: "first_blood", Name: "First Blood", Desc: "Win your first fight.", Event: EnemyDefeated, Count: 1},
+		{ID: "brawler", Name: "Brawler", Desc: "Win ten fights.", Event: EnemyDefeated, Count: 10},
+		{ID: "magpie", Name: "Magpie", Desc: "Pick up fifteen things.", Event: ItemTaken, Count: 15},
+		{ID: "wanderer", Name: "Wanderer", Desc: "Walk through fifty doorways.", Event: RoomEntered, Count: 50},
+		{ID: "gatecrasher", Name: "Gatecrasher", Desc: "Open the ruins gate.", Event: FlagSet, Target: "ruinUnlocked"},
+		{ID: "rivalry", Name: "Rivalry Settled", Desc: "Defeat the rival pirate.", Event: EnemyDefeated, Target: "rival_pirate"},
+		{ID: "tinkerer", Name: "Tinkerer", Desc: "Craft five things.", Event: ItemCrafted, Count: 5},
+		{ID: "sharp_eyes", Name: "Sharp Eyes", Desc: "Find three hidden things.", Event: SecretFound, Count: 3},
+		{ID: "reliable", Name: "Reliable Captain", Desc: "Complete three quests.", Event: QuestCompleted, Count: 3},
+		{ID: "old_salt", Name: "Old Salt", Desc: "Survive a week on the Wild Current.", Event: DayStarted, Count: 7},
+	}
+}
+
+func (g *GameState) CheckAchievements(event Event) {
+	for _, achievement := range Achievements() {
+		if achievement.Event != event.Kind || g.Unlocked[achievement.ID] {
+			continue
+		}
+		if achievement.Target != "" && achievement.Target != event.ID {
+			continue
+		}
+		if achievement.Count > 0 && g.Stats[event.Kind.String()] < achievement.Count {
+			continue
+		}
+		g.Unlocked[achievement.ID] = true
+		g.AddLog(fmt.Sprintf("Achievement unlocked: %s. %s", achievement.Name, achievement.Desc), "story")
+		g.Emit(AchievementUnlocked, achievement.ID, achievement.Name)
+	}
+}
+
+func (g *GameState) AchievementReport() string {
+	lines := []string{"Achievements:"}
+	for _, achievement := range Achievements() {
+		mark := "[ ]"
+		if g.Unlocked[achievement.ID] {
+			mark = "[x]"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s - %s", mark, achievement.Name, achievement.Desc))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) StatsReport() string {
+	if len(g.Stats) == 0 {
+		return "Logbook: nothing recorded yet."
+	}
+	kinds := make([]string, 0, len(g.Stats))
+	for kind := range g.Stats {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := []string{}
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s %d", strings.ReplaceAll(kind, "_", " "), g.Stats[kind]))
+	}
+	return "Logbook: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,9 @@
+-- Reading the old log once steadies the crew.
+function on_use()
+  if flag("navLogRead") then
+    return "You've read every doodle twice."
+  end
+  set("navLogRead")
+  log("The crew crowds round as you read the old captain's notes aloud.")
+  return "Between the doodles: 'Speak the sea's true name at the Ember gate.'"
+end
@@ -0,0 +1,6 @@
+-- The priest remembers an offering.
+function on_talk()
+  if flag("shrineBlessing") then
+    return "The Shrine Keeper bows low. 'The storm spirits remember your offering, Captain.'"
+  end
+end
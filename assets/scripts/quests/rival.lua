@@ -0,0 +1,7 @@
+-- Your rival likes to gloat.
+function on_day_start()
+  if day() == 3 and not flag("rivalTaunt") then
+    set("rivalTaunt")
+    log("A note is nailed to your mast: 'The Core will be mine. -R'")
+  end
+end
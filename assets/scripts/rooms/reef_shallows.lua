@@ -0,0 +1,7 @@
+-- Night tides drag reef beasts into the shallows.
+function on_enter()
+  if (hour() >= 20 or hour() < 5) and chance(30) then
+    spawn("reef_beast")
+    log("Something large stirs in the dark water.")
+  end
+end
@@ -0,0 +1,7 @@
+-- The first step into the Glyph Core.
+function on_enter()
+  if not flag("coreVisited") then
+    set("coreVisited")
+    log("The chamber's hum rises as you step inside. Something old is awake.")
+  end
+end
@@ -21,6 +21,8 @@ type EnemySnapshot struct {
 	MaxDamage  int
 	WantedGain int
 	FleeChance float64
+	Venom      float64
+	Faction    string
 }
 
 func NewCombatState(enemyID string, enemy *Enemy) *CombatState {
@@ -33,6 +35,8 @@ func NewCombatState(enemyID string, enemy *Enemy) *CombatState {
 			MaxDamage:  enemy.MaxDamage,
 			WantedGain: enemy.WantedGain,
 			FleeChance: enemy.FleeChance,
+			Venom:      enemy.Venom,
+			Faction:    enemy.Faction,
 		},
 		Turn: 1,
 	}
@@ -47,7 +51,7 @@ func (c *CombatState) PlayerAttack(state *GameState) string {
 		accuracy = 0.8
 	}
 	if rand.Float64() < accuracy {
-		dmg := rand.Intn(4) + 3 + state.Player.Grit
+		dmg := rand.Intn(4) + 3 + state.Player.Grit + state.CrewCombatBonus()
 		if state.Player.ActiveFruit == "spark_fruit" {
 			dmg += 2
 		}
@@ -55,7 +59,7 @@ func (c *CombatState) PlayerAttack(state *GameState) string {
 		if c.Enemy.HP <= 0 {
 			c.Resolved = true
 			c.Outcome = "enemy_down"
-			state.Wanted += c.Enemy.WantedGain
+			state.FactionCrime(c.Enemy.Faction, c.Enemy.WantedGain)
 			return fmt.Sprintf("You strike true for %d damage. %s collapses.", dmg, c.Enemy.Name)
 		}
 		return fmt.Sprintf("You hit for %d damage.", dmg)
@@ -78,6 +82,10 @@ func (c *CombatState) EnemyAttack(state *GameState) string {
 			dmg = max(1, dmg-2)
 		}
 		state.Player.HP -= dmg
+		if rand.Float64() < c.Enemy.Venom {
+			state.AddStatus("poisoned", 6)
+			return fmt.Sprintf("%s hits you for %d damage. The wound burns. You're poisoned.", c.Enemy.Name, dmg)
+		}
 		return fmt.Sprintf("%s hits you for %d damage.", c.Enemy.Name, dmg)
 	}
 	return fmt.Sprintf("%s swings wide.", c.Enemy.Name)
@@ -1,20 +1,127 @@
 package main
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 )
 
+type CommandArgs struct {
+	Verb   string
+	Object string
+	Target string
+	Text   string
+	Count  int
+	All    bool
+	Except []string
+}
+
+type CommandHandler func(state *GameState, args CommandArgs) []string
+
+type Command struct {
+	Name    string
+	Aliases []string
+	Object  string
+	Target  string
+	Usage   string
+	Help    string
+	Group   string
+	Prompt  string
+	Quick   bool
+	Run     CommandHandler
+}
+
 type CommandProcessor struct {
+	Commands    []*Command
 	Suggestions []string
+	It          string
+	Pending     *Parsed
+	Choices     []string
+	Last        string
+	verbs       map[string]*Command
+	failed      bool
+	nested      bool
 }
 
 func NewCommandProcessor() *CommandProcessor {
-	return &CommandProcessor{Suggestions: []string{"look", "inventory", "talk", "use", "map", "save", "load"}}
+	c := &CommandProcessor{verbs: map[string]*Command{}}
+	c.registerBuiltins()
+	return c
+}
+
+func (c *CommandProcessor) Register(cmd Command) {
+	registered := &cmd
+	c.Commands = append(c.Commands, registered)
+	for _, verb := range append([]string{cmd.Name}, cmd.Aliases...) {
+		c.verbs[verb] = registered
+	}
+	if cmd.Quick {
+		c.Suggestions = append(c.Suggestions, cmd.Name)
+	}
+}
+
+func (c *CommandProcessor) Lookup(verb string) *Command {
+	return c.verbs[verb]
 }
 
 func (c *CommandProcessor) Execute(state *GameState, input string) []string {
+	results := c.sequence(state, input, true)
+	if state.Combat == nil {
+		state.ResolveQuests()
+	}
+	return results
+}
+
+func (c *CommandProcessor) splitSequence(input string) []string {
+	words := strings.Fields(strings.ToLower(input))
+	if len(words) == 0 {
+		return nil
+	}
+	if cmd := c.Lookup(strings.Trim(words[0], ",;")); cmd != nil && (cmd.Name == "say" || cmd.Name == "alias") {
+		return []string{input}
+	}
+	segments := []string{}
+	for _, segment := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ';' }) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func (c *CommandProcessor) sequence(state *GameState, input string, expand bool) []string {
+	segments := c.splitSequence(input)
+	results := []string{}
+	for i, segment := range segments {
+		words := strings.Fields(strings.ToLower(segment))
+		if body, ok := state.Player.Macros[words[0]]; ok && expand && c.Pending == nil {
+			expanded := strings.TrimSpace(body + " " + strings.Join(words[1:], " "))
+			nested := c.nested
+			c.nested = true
+			results = append(results, c.sequence(state, expanded, false)...)
+			c.nested = nested
+			c.Last = segment
+		} else {
+			results = append(results, c.single(state, segment)...)
+		}
+		if i == len(segments)-1 {
+			break
+		}
+		if state.Combat != nil || state.Encounter != nil {
+			results = append(results, "Trouble! The rest of your orders will have to wait.")
+			break
+		}
+		if c.failed {
+			results = append(results, "You stop there: "+strings.Join(segments[i+1:], ", ")+" not done.")
+			break
+		}
+	}
+	return results
+}
+
+func (c *CommandProcessor) single(state *GameState, input string) []string {
 	input = strings.TrimSpace(input)
+	c.failed = false
 	if input == "" {
 		return nil
 	}
@@ -22,96 +129,405 @@ func (c *CommandProcessor) Execute(state *GameState, input string) []string {
 	if len(parts) == 0 {
 		return nil
 	}
-	verb := parts[0]
-	if dir := normalizeDir(verb); dir != "" {
-		return []string{state.Move(dir)}
-	}
-
-	switch verb {
-	case "go", "move", "enter", "dock":
-		if len(parts) < 2 {
-			return []string{"Go where?"}
-		}
-		direction := normalizeDir(parts[1])
-		if direction == "" {
-			return []string{"That direction makes no sense."}
-		}
-		return []string{state.Move(direction)}
-	case "look", "l":
-		return []string{state.Look()}
-	case "examine", "x":
-		if len(parts) < 2 {
-			return []string{"Examine what?"}
-		}
-		return []string{state.Examine(strings.Join(parts[1:], " "))}
-	case "take", "get":
-		if len(parts) < 2 {
-			return []string{"Take what?"}
-		}
-		return []string{state.Take(strings.Join(parts[1:], " "))}
-	case "drop":
-		if len(parts) < 2 {
-			return []string{"Drop what?"}
-		}
-		return []string{state.Drop(strings.Join(parts[1:], " "))}
-	case "inventory", "i":
-		return []string{inventoryText(state)}
-	case "talk":
-		if len(parts) < 2 {
-			return []string{"Talk to whom?"}
-		}
-		return []string{state.Talk(strings.Join(parts[1:], " "))}
-	case "bribe":
-		if len(parts) < 2 {
-			return []string{"Bribe whom?"}
-		}
-		return []string{state.Bribe(strings.Join(parts[1:], " "))}
-	case "threaten":
-		if len(parts) < 2 {
-			return []string{"Threaten whom?"}
-		}
-		return []string{state.Threaten(strings.Join(parts[1:], " "))}
-	case "use":
-		if len(parts) < 2 {
-			return []string{"Use what?"}
-		}
-		target := ""
-		if strings.Contains(strings.ToLower(input), " on ") {
-			segments := strings.SplitN(strings.ToLower(input), " on ", 2)
-			if len(segments) == 2 {
-				target = strings.TrimSpace(segments[1])
-			}
-		}
-		return []string{state.Use(strings.Join(parts[1:], " "), target)}
-	case "attack":
-		if len(parts) < 2 {
-			return []string{"Attack whom?"}
-		}
-		return []string{state.Attack(strings.Join(parts[1:], " "))}
-	case "buy":
-		if len(parts) < 2 {
-			return []string{"Buy what?"}
-		}
-		return []string{state.Buy(strings.Join(parts[1:], " "))}
-	case "sell":
-		if len(parts) < 2 {
-			return []string{"Sell what?"}
-		}
-		return []string{state.Sell(strings.Join(parts[1:], " "))}
-	case "help":
-		return []string{helpText()}
-	case "map":
-		return []string{"The map sits in the left panel. Click a room to travel."}
-	case "save":
-		return []string{state.Save("save1.json")}
-	case "load":
-		return []string{state.Load("save1.json")}
-	case "quit", "exit":
-		state.Flags["quit"] = true
-		return []string{"You lower the sails and end your tale... for now."}
-	default:
+	if cmd := c.Lookup(parts[0]); c.Pending == nil && (cmd == nil || cmd.Name != "again") {
+		c.Last = input
+	}
+	if c.Pending != nil {
+		pending, choices := *c.Pending, c.Choices
+		c.Pending, c.Choices = nil, nil
+		answer := ParseCommand(append([]string{""}, parts...)).Object
+		if answer == "" {
+			answer = strings.Join(parts, " ")
+		}
+		if matches := state.MatchNames(strings.TrimSuffix(answer, " one"), choices); len(matches) == 1 {
+			if pending.Object == "" {
+				pending.Object = matches[0]
+			} else {
+				pending.Target = matches[0]
+			}
+			return c.run(state, pending, parts)
+		}
+	}
+	return c.run(state, ParseCommand(parts), parts)
+}
+
+func (c *CommandProcessor) resolve(state *GameState, cmd *Command, parsed *Parsed) string {
+	slots := []struct {
+		phrase *string
+		scope  string
+	}{{&parsed.Object, cmd.Object}, {&parsed.Target, cmd.Target}}
+	for _, slot := range slots {
+		if *slot.phrase == "" || slot.scope == "" || slot.scope == "text" {
+			continue
+		}
+		if *slot.phrase == "it" || *slot.phrase == "them" {
+			if c.It == "" {
+				return "I'm not sure what you mean by '" + *slot.phrase + "'."
+			}
+			*slot.phrase = c.It
+			continue
+		}
+		matches := state.MatchNames(*slot.phrase, state.scopeIDs(slot.scope))
+		switch len(matches) {
+		case 0:
+			c.failed = c.failed || (slot.phrase == &parsed.Object && slot.scope != "exit")
+		case 1:
+			*slot.phrase = matches[0]
+			if slot.scope != "exit" {
+				c.It = matches[0]
+			}
+		default:
+			*slot.phrase = ""
+			pending := *parsed
+			c.Pending, c.Choices = &pending, matches
+			c.failed = true
+			return state.whichDoYouMean(matches)
+		}
+	}
+	return ""
+}
+
+func (c *CommandProcessor) run(state *GameState, parsed Parsed, parts []string) []string {
+	from := state.Player.Location
+	if dir := normalizeDir(parsed.Verb); dir != "" {
+		result := state.Move(dir)
+		c.failed = state.Player.Location == from
+		return []string{result}
+	}
+	cmd := c.Lookup(parsed.Verb)
+	if cmd == nil {
+		c.failed = true
 		return []string{"Unknown command. Type HELP for options."}
 	}
+	if question := c.resolve(state, cmd, &parsed); question != "" {
+		return []string{question}
+	}
+	if cmd.Prompt != "" && parsed.Object == "" && !parsed.All {
+		c.failed = true
+		return []string{cmd.Prompt}
+	}
+	state.Refused = false
+	results := cmd.Run(state, CommandArgs{Verb: parsed.Verb, Object: parsed.Object, Target: parsed.Target, Text: strings.Join(parts[1:], " "), Count: max(1, parsed.Count), All: parsed.All, Except: parsed.Except})
+	c.failed = c.failed || state.Refused
+	state.checkLight()
+	state.checkEquipped()
+	if cmd.Object == "exit" {
+		c.failed = c.failed || state.Player.Location == from
+	}
+	return results
+}
+
+func (c *CommandProcessor) DefineMacro(state *GameState, text string) string {
+	if strings.TrimSpace(text) == "" {
+		return state.MacroReport()
+	}
+	name, body, ok := strings.Cut(text, "=")
+	name, body = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(body)
+	if !ok || name == "" || body == "" || strings.ContainsAny(name, " ,;") {
+		return "Usage: ALIAS <name> = <commands>"
+	}
+	if c.Lookup(name) != nil || normalizeDir(name) != "" {
+		return "'" + name + "' is already a command."
+	}
+	for _, segment := range c.splitSequence(body) {
+		if cmd := c.Lookup(strings.Fields(strings.ToLower(segment))[0]); cmd != nil && cmd.Name == "again" {
+			return "An alias can't use AGAIN."
+		}
+	}
+	state.Player.Macros[name] = body
+	return "Alias set: " + name + " = " + body
+}
+
+func (c *CommandProcessor) Complete(state *GameState, input string) []string {
+	base := strings.ToLower(strings.TrimLeft(input, " "))
+	if strings.TrimSpace(base) == "" {
+		return nil
+	}
+	options := []string{}
+	for _, cmd := range c.Commands {
+		options = append(options, cmd.Name)
+		if cmd.Object == "" || cmd.Object == "text" {
+			continue
+		}
+		for _, id := range state.scopeIDs(cmd.Object) {
+			name, _ := state.describe(id)
+			option := cmd.Name + " " + strings.ToLower(name)
+			if !contains(options, option) {
+				options = append(options, option)
+			}
+		}
+	}
+	matches := []string{}
+	for _, option := range options {
+		if strings.HasPrefix(option, base) && option != base {
+			matches = append(matches, option)
+		}
+	}
+	return matches
+}
+
+func (c *CommandProcessor) registerBuiltins() {
+	for _, cmd := range []Command{
+		{Name: "go", Aliases: []string{"move", "enter", "dock"}, Object: "exit", Group: "Movement", Usage: "GO NORTH, NORTH, N (also south/east/west)", Help: "Walk through an exit. Directions can be typed on their own.", Prompt: "Go where?", Run: func(state *GameState, args CommandArgs) []string {
+			direction := normalizeDir(args.Object)
+			if direction == "" {
+				return []string{"That direction makes no sense."}
+			}
+			return []string{state.Move(direction)}
+		}},
+		{Name: "look", Aliases: []string{"l"}, Group: "Actions", Usage: "LOOK", Help: "Describe your surroundings.", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Look()}
+		}},
+		{Name: "examine", Aliases: []string{"x", "inspect"}, Object: "near", Group: "Actions", Usage: "EXAMINE <thing>", Help: "Take a closer look at an item, person or foe. LOOK AT works too.", Prompt: "Examine what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Examine(args.Object)}
+		}},
+		{Name: "take", Aliases: []string{"get"}, Object: "room", Target: "container", Group: "Actions", Usage: "TAKE [n|ALL] <item> [FROM <container>]", Help: "Pick something up. PICK UP works too. TAKE ALL [EXCEPT <item> AND <item>] sweeps the room.", Prompt: "Take what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Target != "" {
+				return []string{state.TakeFrom(args.Object, args.Target, args.Count, args.All)}
+			}
+			if args.All && args.Object == "" {
+				return []string{state.TakeAll(args.Except)}
+			}
+			if args.All {
+				args.Count = len(state.Room().Items)
+			}
+			return []string{state.TakeCount(args.Object, args.Count)}
+		}},
+		{Name: "drop", Object: "inventory", Group: "Actions", Usage: "DROP [n|ALL] <item>", Help: "Leave something you carry on the ground. DROP ALL [EXCEPT <item>] empties your pockets.", Prompt: "Drop what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.All && args.Object == "" {
+				return []string{state.DropAll(args.Except)}
+			}
+			if args.All {
+				args.Count = len(state.Player.Inventory)
+			}
+			return []string{state.DropCount(args.Object, args.Count)}
+		}},
+		{Name: "put", Aliases: []string{"place"}, Object: "inventory", Target: "container", Group: "Containers", Usage: "PUT [n] <item> IN <container>", Help: "Stash something in a chest, satchel or crate. PUT X IN HOLD stows it aboard.", Prompt: "Put what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Target == "" {
+				return []string{"Put it in what?"}
+			}
+			return []string{state.PutIn(args.Object, args.Target, args.Count)}
+		}},
+		{Name: "search", Object: "container", Group: "Containers", Usage: "SEARCH [container]", Help: "Search the room for hidden things, which takes an hour and your wits, or the right tool. SEARCH <container> or LOOK IN <container> checks inside something.", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Object == "" || args.Object == "room" || args.Object == "here" || args.Object == "around" {
+				return []string{state.Search()}
+			}
+			return []string{state.LookIn(args.Object)}
+		}},
+		{Name: "open", Object: "container", Group: "Containers", Usage: "OPEN <container>", Help: "Open a chest, crate or bag.", Prompt: "Open what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.OpenContainer(args.Object)}
+		}},
+		{Name: "close", Aliases: []string{"shut"}, Object: "container", Group: "Containers", Usage: "CLOSE <container>", Help: "Close a container.", Prompt: "Close what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.CloseContainer(args.Object)}
+		}},
+		{Name: "lock", Object: "container", Group: "Containers", Usage: "LOCK <container>", Help: "Lock a container. You need its key.", Prompt: "Lock what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.LockContainer(args.Object, true)}
+		}},
+		{Name: "unlock", Object: "container", Group: "Containers", Usage: "UNLOCK <container>", Help: "Unlock a container with its key.", Prompt: "Unlock what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.LockContainer(args.Object, false)}
+		}},
+		{Name: "combine", Aliases: []string{"attach", "mix"}, Object: "inventory", Target: "inventory", Group: "Crafting", Usage: "COMBINE <item> WITH <item>", Help: "Try two things together. Anything that works goes in your recipe book.", Prompt: "Combine what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Target == "" {
+				return []string{"Combine it with what?"}
+			}
+			return []string{state.Combine(args.Object, args.Target)}
+		}},
+		{Name: "craft", Aliases: []string{"make"}, Object: "text", Group: "Crafting", Usage: "CRAFT <recipe>", Help: "Make something from your recipe book. Some recipes need a forge or a shipyard.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Craft(args.Object)}
+		}},
+		{Name: "recipes", Aliases: []string{"book"}, Group: "Crafting", Usage: "RECIPES", Help: "Open your recipe book.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.RecipeBook()}
+		}},
+		{Name: "eat", Object: "inventory", Group: "Actions", Usage: "EAT [item]", Help: "Eat something you carry, a meal at the tavern, or from the galley aboard.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Eat(args.Object)}
+		}},
+		{Name: "drink", Aliases: []string{"swig", "apply"}, Object: "inventory", Group: "Actions", Usage: "DRINK <item>", Help: "Drink, swallow or rub on something you carry.", Prompt: "Drink what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Consume(args.Object)}
+		}},
+		{Name: "light", Aliases: []string{"ignite", "kindle"}, Object: "inventory", Group: "Actions", Usage: "LIGHT <lantern/torch/flare>", Help: "Light a lantern, torch or flare. Ruins and night streets are dark without one.", Prompt: "Light what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.LightUp(args.Object)}
+		}},
+		{Name: "douse", Aliases: []string{"extinguish", "snuff"}, Object: "inventory", Group: "Actions", Usage: "DOUSE <light>", Help: "Put out your light to save fuel.", Prompt: "Douse what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Douse(args.Object)}
+		}},
+		{Name: "sleep", Aliases: []string{"rest"}, Group: "Actions", Usage: "SLEEP", Help: "Sleep eight hours in your cabin or a tavern bunk. Clears fatigue and heals.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Rest()}
+		}},
+		{Name: "condition", Aliases: []string{"health"}, Group: "Actions", Usage: "CONDITION", Help: "How hungry, tired and otherwise afflicted you are.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{"HP " + strconv.Itoa(state.Player.HP) + "/" + strconv.Itoa(state.Player.MaxHP) + ". " + state.ConditionReport() + "."}
+		}},
+		{Name: "inventory", Aliases: []string{"i", "inv"}, Group: "Actions", Usage: "INVENTORY", Help: "List what you carry.", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{inventoryText(state)}
+		}},
+		{Name: "talk", Object: "npc", Group: "Social", Usage: "TALK <npc>", Help: "Strike up a conversation. TALK TO works too.", Prompt: "Talk to whom?", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Talk(args.Object)}
+		}},
+		{Name: "bribe", Object: "npc", Group: "Social", Usage: "BRIBE <npc>", Help: "Grease a palm with a bribe pouch.", Prompt: "Bribe whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Bribe(args.Object)}
+		}},
+		{Name: "threaten", Object: "npc", Group: "Social", Usage: "THREATEN <npc>", Help: "Lean on someone. They will remember it.", Prompt: "Threaten whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Threaten(args.Object)}
+		}},
+		{Name: "use", Object: "inventory", Target: "near", Group: "Use", Usage: "USE <item> [ON <target>]", Help: "Use an item, on its own or on someone or something. IT and THEM mean the last thing named.", Prompt: "Use what?", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Use(args.Object, args.Target)}
+		}},
+		{Name: "give", Aliases: []string{"show", "offer"}, Object: "inventory", Target: "npc", Group: "Use", Usage: "GIVE <item> TO <npc>", Help: "Hand an item to someone nearby.", Prompt: "Give what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Target == "" {
+				return []string{"To whom?"}
+			}
+			return []string{state.Use(args.Object, args.Target)}
+		}},
+		{Name: "attack", Aliases: []string{"fight"}, Object: "enemy", Group: "Combat", Usage: "ATTACK <enemy>", Help: "Start a fight with a foe in the room.", Prompt: "Attack whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Attack(args.Object)}
+		}},
+		{Name: "wares", Aliases: []string{"list", "shop"}, Group: "Economy", Usage: "WARES", Help: "See what the merchants here are selling.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Wares()}
+		}},
+		{Name: "buy", Object: "stock", Target: "npc", Group: "Economy", Usage: "BUY [n] <item>", Help: "Buy from a merchant in the room, e.g. BUY 3 RUM.", Prompt: "Buy what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.BuyCount(args.Object, args.Count)}
+		}},
+		{Name: "sell", Object: "inventory", Target: "npc", Group: "Economy", Usage: "SELL [n|ALL] <item> [TO <npc>]", Help: "Sell to a merchant. Fences take contraband. SELL ALL [EXCEPT <item>] sells your trade goods and contraband.", Prompt: "Sell what?", Run: func(state *GameState, args CommandArgs) []string {
+			if args.All && args.Object == "" {
+				return []string{state.SellAll(args.Target, args.Except)}
+			}
+			if args.All {
+				args.Count = len(state.Player.Inventory)
+			}
+			return []string{state.SellCount(args.Object, args.Target, args.Count)}
+		}},
+		{Name: "haggle", Object: "npc", Group: "Economy", Usage: "HAGGLE [npc]", Help: "Talk a merchant's prices down for the day.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Haggle(args.Object)}
+		}},
+		{Name: "ship", Group: "Ship", Usage: "SHIP", Help: "Report on hull, supplies and cargo.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.ShipReport()}
+		}},
+		{Name: "repair", Group: "Ship", Usage: "REPAIR", Help: "Pay a shipwright to patch the hull.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Repair()}
+		}},
+		{Name: "provision", Aliases: []string{"supply"}, Group: "Ship", Usage: "PROVISION", Help: "Stock up on supplies for the voyage.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Provision()}
+		}},
+		{Name: "stow", Object: "inventory", Group: "Ship", Usage: "STOW <item>", Help: "Put an item in the ship's hold.", Prompt: "Stow what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Stow(args.Object)}
+		}},
+		{Name: "unstow", Object: "hold", Group: "Ship", Usage: "UNSTOW <item>", Help: "Take an item back out of the hold.", Prompt: "Unstow what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Unstow(args.Object)}
+		}},
+		{Name: "hide", Object: "inventory", Group: "Ship", Usage: "HIDE <item>", Help: "Tuck contraband into the hidden compartment.", Prompt: "Hide what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Hide(args.Object)}
+		}},
+		{Name: "unhide", Object: "hidden", Group: "Ship", Usage: "UNHIDE <item>", Help: "Take contraband out of hiding.", Prompt: "Unhide what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Unhide(args.Object)}
+		}},
+		{Name: "crew", Group: "Crew", Usage: "CREW", Help: "List your crew and their morale.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.CrewReport()}
+		}},
+		{Name: "recruit", Aliases: []string{"hire"}, Object: "npc", Group: "Crew", Usage: "RECRUIT <npc>", Help: "Invite someone aboard.", Prompt: "Recruit whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Recruit(args.Object)}
+		}},
+		{Name: "dismiss", Object: "companion", Group: "Crew", Usage: "DISMISS <npc>", Help: "Let a crew member go.", Prompt: "Dismiss whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Dismiss(args.Object)}
+		}},
+		{Name: "say", Aliases: []string{"answer", "speak"}, Object: "text", Group: "Puzzles", Usage: "SAY <words>", Help: "Speak aloud. Some doors listen.", Prompt: "Say what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Say(args.Text)}
+		}},
+		{Name: "pull", Aliases: []string{"press", "push", "step", "touch"}, Object: "text", Group: "Puzzles", Usage: "PULL <lever>, PRESS <rune/plate>", Help: "Work a lever, plate or rune.", Prompt: "What do you want to work?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Operate(args.Object)}
+		}},
+		{Name: "hint", Group: "Puzzles", Usage: "HINT", Help: "Think hard about the puzzle in front of you.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Hint()}
+		}},
+		{Name: "rumors", Aliases: []string{"rumours", "intel"}, Group: "Intel", Usage: "RUMORS", Help: "Review the rumors you have heard. USE RUM ON <npc> loosens tongues.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.RumorReport()}
+		}},
+		{Name: "gossip", Object: "npc", Group: "Intel", Usage: "GOSSIP <npc>", Help: "Chat someone up for news, once a day.", Prompt: "Gossip with whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Gossip(args.Object)}
+		}},
+		{Name: "pay", Object: "npc", Group: "Intel", Usage: "PAY <npc>", Help: "Pay for a rumor.", Prompt: "Pay whom?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.PayForRumor(args.Object)}
+		}},
+		{Name: "reputation", Aliases: []string{"rep", "standing"}, Group: "Standing", Usage: "REPUTATION", Help: "Show how the factions see you.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.ReputationReport(), state.HeatReport()}
+		}},
+		{Name: "heat", Group: "Standing", Usage: "HEAT", Help: "Show how hard the Navy is looking for you on each island.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.HeatReport()}
+		}},
+		{Name: "payoff", Group: "Standing", Usage: "PAYOFF", Help: "Settle your bounty with a Navy officer.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.PayBounty()}
+		}},
+		{Name: "wear", Aliases: []string{"don"}, Object: "inventory", Group: "Standing", Usage: "WEAR <disguise>", Help: "Put on a disguise.", Prompt: "Wear what?", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Wear(args.Object)}
+		}},
+		{Name: "unwear", Aliases: []string{"undress"}, Group: "Standing", Usage: "UNWEAR", Help: "Take off your disguise.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Unwear()}
+		}},
+		{Name: "help", Aliases: []string{"?"}, Object: "text", Group: "Utility", Usage: "HELP [command]", Help: "List commands, or explain one.", Run: func(state *GameState, args CommandArgs) []string {
+			if args.Object != "" {
+				return []string{c.CommandHelp(args.Object)}
+			}
+			return []string{c.HelpText()}
+		}},
+		{Name: "again", Aliases: []string{"g"}, Group: "Utility", Usage: "AGAIN", Help: "Repeat your last command. G for short. Chain commands with commas or semicolons: TAKE ROPE, N; E.", Run: func(state *GameState, args CommandArgs) []string {
+			if c.Last == "" {
+				return []string{"There's nothing to repeat."}
+			}
+			if c.nested {
+				c.failed = true
+				return []string{"AGAIN can't repeat itself."}
+			}
+			c.nested = true
+			results := c.sequence(state, c.Last, true)
+			c.nested = false
+			return results
+		}},
+		{Name: "alias", Aliases: []string{"macro"}, Object: "text", Group: "Utility", Usage: "ALIAS <name> = <commands>", Help: "Define a shortcut, e.g. ALIAS LOOT = TAKE ROPE, TAKE FLARE. ALIAS alone lists them.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{c.DefineMacro(state, args.Text)}
+		}},
+		{Name: "unalias", Object: "text", Group: "Utility", Usage: "UNALIAS <name>", Help: "Forget a shortcut.", Prompt: "Unalias what?", Run: func(state *GameState, args CommandArgs) []string {
+			if _, ok := state.Player.Macros[args.Text]; !ok {
+				return []string{"No alias called '" + args.Text + "'."}
+			}
+			delete(state.Player.Macros, args.Text)
+			return []string{"Alias removed: " + args.Text}
+		}},
+		{Name: "achievements", Aliases: []string{"feats"}, Group: "Utility", Usage: "ACHIEVEMENTS", Help: "List achievements and which you have earned.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.AchievementReport()}
+		}},
+		{Name: "stats", Aliases: []string{"logbook"}, Group: "Utility", Usage: "STATS", Help: "Show your captain's logbook tallies.", Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.StatsReport()}
+		}},
+		{Name: "map", Help: "Where to find the map.", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{"The map sits in the left panel. Click a room to travel."}
+		}},
+		{Name: "save", Group: "Utility", Usage: "SAVE", Help: "Save your game.", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Save("save1.json")}
+		}},
+		{Name: "load", Group: "Utility", Usage: "LOAD", Help: "Load your saved game.", Quick: true, Run: func(state *GameState, args CommandArgs) []string {
+			return []string{state.Load("save1.json")}
+		}},
+		{Name: "quit", Aliases: []string{"exit"}, Group: "Utility", Usage: "QUIT", Help: "End the game.", Run: func(state *GameState, args CommandArgs) []string {
+			state.Flags["quit"] = true
+			return []string{"You lower the sails and end your tale... for now."}
+		}},
+	} {
+		c.Register(cmd)
+	}
+}
+
+func (g *GameState) MacroReport() string {
+	if len(g.Player.Macros) == 0 {
+		return "No aliases yet. Try ALIAS LOOT = TAKE ROPE, TAKE FLARE."
+	}
+	names := make([]string, 0, len(g.Player.Macros))
+	for name := range g.Player.Macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{"Aliases:"}
+	for _, name := range names {
+		lines = append(lines, "- "+name+" = "+g.Player.Macros[name])
+	}
+	return strings.Join(lines, "\n")
 }
 
 func normalizeDir(dir string) string {
@@ -142,25 +558,56 @@ func inventoryText(state *GameState) string {
 		return "Your pockets are empty."
 	}
 	lines := []string{"	Inventory:"}
-	for _, itemID := range state.Player.Inventory {
-		if item, ok := state.Items[itemID]; ok {
-			lines = append(lines, "- "+item.Name)
+	for _, itemID := range stackIDs(state.Player.Inventory) {
+		if _, ok := state.Items[itemID]; ok {
+			lines = append(lines, "- "+state.stackName(itemID, countID(state.Player.Inventory, itemID)))
+		}
+	}
+	for _, itemID := range state.carriedContainers() {
+		if held := state.container(itemID).Items; len(held) > 0 {
+			lines = append(lines, "In the "+state.Items[itemID].Name+": "+state.ListItemNames(held))
 		}
 	}
 	lines = append(lines, "Slots used: "+strconv.Itoa(state.InventorySlots())+"/"+strconv.Itoa(state.Player.MaxSlots))
+	if light := state.LightReport(); light != "" {
+		lines = append(lines, light)
+	}
 	return strings.Join(lines, "\n")
 }
 
-func helpText() string {
-	return strings.Join([]string{
-		"Commands:",
-		"Movement: GO NORTH, NORTH, N (also south/east/west)",
-		"Actions: LOOK, EXAMINE <thing>, TAKE <item>, DROP <item>",
-		"Social: TALK <npc>, BRIBE <npc>, THREATEN <npc>",
-		"Use: USE <item> [ON <target>]",
-		"Combat: ATTACK <enemy>",
-		"Economy: BUY <item>, SELL <item>",
-		"Utility: HELP, SAVE, LOAD, QUIT",
-		"Goal: Collect three Glyph Stone fragments and escape with the treasure core.",
-	}, "\n")
+func (c *CommandProcessor) HelpText() string {
+	lines := []string{"Commands:"}
+	groups := []string{}
+	usages := map[string][]string{}
+	for _, cmd := range c.Commands {
+		if cmd.Group == "" || cmd.Usage == "" {
+			continue
+		}
+		if _, ok := usages[cmd.Group]; !ok {
+			groups = append(groups, cmd.Group)
+		}
+		usages[cmd.Group] = append(usages[cmd.Group], cmd.Usage)
+	}
+	for _, group := range groups {
+		lines = append(lines, group+": "+strings.Join(usages[group], ", "))
+	}
+	lines = append(lines, "Type HELP <command> for details.")
+	lines = append(lines, "Goal: Collect three Glyph Stone fragments and escape with the treasure core.")
+	return strings.Join(lines, "\n")
+}
+
+func (c *CommandProcessor) CommandHelp(verb string) string {
+	cmd := c.Lookup(strings.Fields(verb)[0])
+	if cmd == nil {
+		return "No command called '" + verb + "'. Type HELP for a list."
+	}
+	usage := cmd.Usage
+	if usage == "" {
+		usage = strings.ToUpper(cmd.Name)
+	}
+	lines := []string{usage, cmd.Help}
+	if len(cmd.Aliases) > 0 {
+		lines = append(lines, "Also: "+strings.ToUpper(strings.Join(cmd.Aliases, ", ")))
+	}
+	return strings.Join(lines, "\n")
 }
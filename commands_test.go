@@ -0,0 +1,114 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAliases(t *testing.T) {
+	c := NewCommandProcessor()
+	for _, cmd := range c.Commands {
+		for _, verb := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if got := c.Lookup(verb); got != cmd {
+				t.Errorf("Lookup(%q) = %v, want the %s command", verb, got, cmd.Name)
+			}
+		}
+	}
+	if got := c.CommandHelp("get"); !strings.HasPrefix(got, "TAKE [n|ALL] <item>") || !strings.Contains(got, "Also: GET") {
+		t.Errorf("CommandHelp(get) = %q", got)
+	}
+	if got := c.CommandHelp("dance"); got != "No command called 'dance'. Type HELP for a list." {
+		t.Errorf("CommandHelp(dance) = %q", got)
+	}
+}
+
+func TestRegisterCommand(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	c.Register(Command{Name: "wave", Aliases: []string{"salute"}, Object: "npc", Group: "Actions", Usage: "WAVE <person>", Help: "Wave at someone.", Run: func(state *GameState, args CommandArgs) []string {
+		return []string{"You wave at " + args.Object + "."}
+	}})
+	if got := c.Execute(g, "salute the cook"); !reflect.DeepEqual(got, []string{"You wave at cook."}) {
+		t.Errorf("salute the cook = %q", got)
+	}
+	if !strings.Contains(c.HelpText(), "WAVE <person>") {
+		t.Errorf("HelpText() is missing the registered command")
+	}
+	if got := c.Complete(g, "wave"); !reflect.DeepEqual(got, []string{"wave ship cook"}) {
+		t.Errorf("Complete(wave) = %q", got)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	if got := c.Complete(g, "take co"); !reflect.DeepEqual(got, []string{"take coil of rope"}) {
+		t.Errorf("Complete(take co) = %q", got)
+	}
+	if got := c.Complete(g, "  "); got != nil {
+		t.Errorf("Complete(blank) = %q, want nil", got)
+	}
+}
+
+func TestSequenceStopsOnFailure(t *testing.T) {
+	cases := []struct {
+		input string
+		stop  string
+	}{
+		{"take rope, take anchor, take flare", "You stop there: take flare not done."},
+		{"take rope; wear rope; take flare", "You stop there: take flare not done."},
+	}
+	for _, c := range cases {
+		g := NewGameState()
+		got := NewCommandProcessor().Execute(g, c.input)
+		if got[len(got)-1] != c.stop {
+			t.Errorf("%q ended with %q, want %q", c.input, got[len(got)-1], c.stop)
+		}
+		if !contains(g.Player.Inventory, "rope") || contains(g.Player.Inventory, "flare") {
+			t.Errorf("%q left inventory %v, want the rope and no flare", c.input, g.Player.Inventory)
+		}
+	}
+}
+
+func TestSayKeepsCommas(t *testing.T) {
+	g := NewGameState()
+	got := NewCommandProcessor().Execute(g, "say ahoy, matey")
+	if !reflect.DeepEqual(got, []string{"You say 'ahoy, matey'. No one seems to care."}) {
+		t.Errorf("say with a comma = %q", got)
+	}
+}
+
+func TestMacrosAndAgain(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	c.Execute(g, "alias loot = take rope, take flare")
+	c.Execute(g, "loot")
+	if !contains(g.Player.Inventory, "rope") || !contains(g.Player.Inventory, "flare") {
+		t.Fatalf("loot left inventory %v", g.Player.Inventory)
+	}
+	g.Player.Inventory = nil
+	g.Room().Items = append(g.Room().Items, "rope", "flare")
+	c.Execute(g, "again")
+	if len(g.Player.Inventory) != 2 {
+		t.Errorf("again after a macro took %v, want both items", g.Player.Inventory)
+	}
+	for _, body := range []string{"g", "look, again"} {
+		if got := c.Execute(g, "alias loop = "+body); got[0] != "An alias can't use AGAIN." {
+			t.Errorf("alias loop = %s: %q", body, got)
+		}
+	}
+}
+
+func TestAgainCannotRecurse(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	g.Player.Macros["loop"] = "g"
+	want := "There's nothing to repeat."
+	for i := 0; i < 3; i++ {
+		if got := c.Execute(g, "loop"); !reflect.DeepEqual(got, []string{want}) {
+			t.Errorf("loop #%d = %q, want %q", i+1, got, want)
+		}
+		want = "AGAIN can't repeat itself."
+	}
+}
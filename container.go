@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Container struct {
+	ID       string
+	Capacity int
+	Fixed    bool
+	Key      string
+	Closed   bool
+	Locked   bool
+	Contents []string
+}
+
+type ContainerState struct {
+	Items  []string
+	Open   bool
+	Locked bool
+}
+
+func Containers() map[string]Container {
+	return map[string]Container{
+		"sea_chest":    {ID: "sea_chest", Capacity: 8, Fixed: true, Key: "chest_key", Closed: true, Locked: true, Contents: []string{"medkit", "pearl"}},
+		"satchel":      {ID: "satchel", Capacity: 4},
+		"buried_cache": {ID: "buried_cache", Capacity: 6, Fixed: true, Closed: true, Contents: []string{"pearl", "pearl", "smoke_bomb"}},
+		"forge_crates": {ID: "forge_crates", Capacity: 10, Fixed: true, Closed: true, Contents: []string{"spice", "spice", "smoke_bomb"}},
+	}
+}
+
+func (g *GameState) InitContainers() {
+	g.Containers = map[string]*ContainerState{}
+	for id, container := range Containers() {
+		g.Containers[id] = &ContainerState{Items: append([]string{}, container.Contents...), Open: !container.Closed, Locked: container.Locked}
+	}
+}
+
+func (g *GameState) container(id string) *ContainerState {
+	state, ok := g.Containers[id]
+	if !ok {
+		state = &ContainerState{Items: []string{}, Open: true}
+		g.Containers[id] = state
+	}
+	return state
+}
+
+func (g *GameState) reachableContainers() []string {
+	ids := []string{}
+	for _, itemID := range append(append([]string{}, g.Player.Inventory...), g.visibleItems()...) {
+		if _, ok := Containers()[itemID]; ok && !contains(ids, itemID) {
+			ids = append(ids, itemID)
+		}
+	}
+	return ids
+}
+
+func (g *GameState) carriedContainers() []string {
+	ids := []string{}
+	for _, itemID := range stackIDs(g.Player.Inventory) {
+		if _, ok := Containers()[itemID]; ok {
+			ids = append(ids, itemID)
+		}
+	}
+	return ids
+}
+
+func (g *GameState) Carried() []string {
+	carried := append([]string{}, g.Player.Inventory...)
+	for _, itemID := range g.carriedContainers() {
+		carried = append(carried, g.container(itemID).Items...)
+	}
+	return carried
+}
+
+func (g *GameState) removeCarried(id string) {
+	if contains(g.Player.Inventory, id) {
+		g.Player.Inventory = removeOne(g.Player.Inventory, id)
+		return
+	}
+	for _, itemID := range g.carriedContainers() {
+		if state := g.container(itemID); contains(state.Items, id) {
+			state.Items = removeOne(state.Items, id)
+			return
+		}
+	}
+}
+
+func (g *GameState) notEmpty(itemID string) string {
+	if _, ok := Containers()[itemID]; ok && len(g.container(itemID).Items) > 0 {
+		return fmt.Sprintf("Empty the %s first.", g.Items[itemID].Name)
+	}
+	return ""
+}
+
+func isHold(name string) bool {
+	for _, word := range nameWords(name) {
+		if word == "hold" || word == "cargo" {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GameState) findContainer(name string) (string, string) {
+	itemID := g.FindItem(name, g.reachableContainers())
+	if itemID != "" {
+		return itemID, ""
+	}
+	if g.FindItem(name, append(append([]string{}, g.Player.Inventory...), g.visibleItems()...)) != "" {
+		return "", "That can't hold anything."
+	}
+	return "", "You don't see anything like that."
+}
+
+func (g *GameState) OpenContainer(name string) string {
+	itemID, refusal := g.findContainer(name)
+	if itemID == "" {
+		return g.refuse(refusal)
+	}
+	state := g.container(itemID)
+	item := g.Items[itemID]
+	if state.Open {
+		return fmt.Sprintf("The %s is already open.", item.Name)
+	}
+	if state.Locked {
+		return g.refuse(fmt.Sprintf("The %s is locked.", item.Name))
+	}
+	if room := g.Room(); contains(room.Items, itemID) && len(room.Enemies) > 0 {
+		return g.refuse(fmt.Sprintf("Not with the %s watching.", g.Enemies[room.Enemies[0]].Name))
+	}
+	state.Open = true
+	return fmt.Sprintf("You open the %s. %s", item.Name, g.containerContents(itemID))
+}
+
+func (g *GameState) CloseContainer(name string) string {
+	itemID, refusal := g.findContainer(name)
+	if itemID == "" {
+		return g.refuse(refusal)
+	}
+	state := g.container(itemID)
+	if !state.Open {
+		return fmt.Sprintf("The %s is already closed.", g.Items[itemID].Name)
+	}
+	state.Open = false
+	return fmt.Sprintf("You close the %s.", g.Items[itemID].Name)
+}
+
+func (g *GameState) LockContainer(name string, locking bool) string {
+	itemID, refusal := g.findContainer(name)
+	if itemID == "" {
+		return g.refuse(refusal)
+	}
+	container := Containers()[itemID]
+	state := g.container(itemID)
+	item := g.Items[itemID]
+	if container.Key == "" {
+		return g.refuse(fmt.Sprintf("The %s has no lock.", item.Name))
+	}
+	if state.Locked == locking {
+		if locking {
+			return fmt.Sprintf("The %s is already locked.", item.Name)
+		}
+		return fmt.Sprintf("The %s isn't locked.", item.Name)
+	}
+	if !g.HasItem(container.Key) {
+		return g.refuse(fmt.Sprintf("You need the %s.", g.Items[container.Key].Name))
+	}
+	if locking {
+		state.Open = false
+		state.Locked = true
+		return fmt.Sprintf("You shut and lock the %s.", item.Name)
+	}
+	state.Locked = false
+	return fmt.Sprintf("The %s turns with a click. The %s is unlocked.", g.Items[container.Key].Name, item.Name)
+}
+
+func (g *GameState) containerContents(itemID string) string {
+	state := g.container(itemID)
+	if len(state.Items) == 0 {
+		return "It's empty."
+	}
+	return "Inside: " + g.ListItemNames(state.Items) + "."
+}
+
+func (g *GameState) containerUsed(itemID string) int {
+	used := 0
+	for _, id := range g.container(itemID).Items {
+		used += g.Items[id].Slots
+	}
+	return used
+}
+
+func (g *GameState) slotsFor(itemID string) int {
+	slots := g.Items[itemID].Slots
+	if _, ok := Containers()[itemID]; ok {
+		slots += g.containerUsed(itemID)
+	}
+	return slots
+}
+
+func (g *GameState) LookIn(name string) string {
+	if g.InDarkness() {
+		return g.refuse("It's too dark to see inside.")
+	}
+	if isHold(name) {
+		if g.Room().Island != "Ship" {
+			return g.refuse("You need to be aboard to reach the hold.")
+		}
+		if len(g.Ship.Hold) == 0 {
+			return "The hold is empty."
+		}
+		return fmt.Sprintf("The hold (%d/%d) holds: %s.", g.Ship.HoldUsed(g.Items), g.Ship.HoldSlots, g.ListItemNames(g.Ship.Hold))
+	}
+	itemID, refusal := g.findContainer(name)
+	if itemID == "" {
+		return g.refuse(refusal)
+	}
+	if !g.container(itemID).Open {
+		if g.container(itemID).Locked {
+			return g.refuse(fmt.Sprintf("The %s is closed and locked.", g.Items[itemID].Name))
+		}
+		return g.refuse(fmt.Sprintf("The %s is closed.", g.Items[itemID].Name))
+	}
+	return fmt.Sprintf("%s (%d/%d). %s", g.Items[itemID].Name, g.containerUsed(itemID), Containers()[itemID].Capacity, g.containerContents(itemID))
+}
+
+func (g *GameState) PutIn(itemName string, containerName string, count int) string {
+	if isHold(containerName) {
+		return g.Stow(itemName)
+	}
+	itemID := g.FindItem(itemName, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	containerID, refusal := g.findContainer(containerName)
+	if containerID == "" {
+		return g.refuse(refusal)
+	}
+	item, box := g.Items[itemID], g.Items[containerID]
+	if itemID == containerID {
+		return g.refuse("That would be a neat trick.")
+	}
+	if !g.container(containerID).Open {
+		return g.refuse(fmt.Sprintf("The %s is closed.", box.Name))
+	}
+	if refusal := g.notEmpty(itemID); refusal != "" {
+		return g.refuse(refusal)
+	}
+	count = min(count, countID(g.Player.Inventory, itemID))
+	put := 0
+	for put < count && g.containerUsed(containerID)+item.Slots <= Containers()[containerID].Capacity {
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+		state := g.container(containerID)
+		state.Items = append(state.Items, itemID)
+		put++
+	}
+	if put == 0 {
+		return g.refuse(fmt.Sprintf("The %s won't fit in the %s.", item.Name, box.Name))
+	}
+	if put == 1 {
+		return fmt.Sprintf("You put the %s in the %s.", item.Name, box.Name)
+	}
+	return fmt.Sprintf("You put %s in the %s.", g.stackName(itemID, put), box.Name)
+}
+
+func (g *GameState) TakeFrom(itemName string, containerName string, count int, all bool) string {
+	if isHold(containerName) {
+		return g.Unstow(itemName)
+	}
+	containerID, refusal := g.findContainer(containerName)
+	if containerID == "" {
+		return g.refuse(refusal)
+	}
+	state := g.container(containerID)
+	box := g.Items[containerID]
+	if !state.Open {
+		return g.refuse(fmt.Sprintf("The %s is closed.", box.Name))
+	}
+	if g.InDarkness() && !g.SkillCheck("wits") {
+		return g.refuse(fmt.Sprintf("You rummage in the %s but can't tell one thing from another in the dark.", box.Name))
+	}
+	ids := stackIDs(state.Items)
+	if itemName != "" {
+		matches := g.MatchNames(itemName, state.Items)
+		if len(matches) == 0 {
+			return g.refuse(fmt.Sprintf("There's nothing like that in the %s.", box.Name))
+		}
+		ids = matches[:1]
+	} else if !all {
+		return g.refuse("Take what?")
+	}
+	carried := contains(g.Player.Inventory, containerID)
+	lines := []string{}
+	for _, itemID := range ids {
+		item := g.Items[itemID]
+		want := count
+		if all {
+			want = countID(state.Items, itemID)
+		}
+		taken := 0
+		for taken < want && contains(state.Items, itemID) && (carried || g.InventorySlots()+g.slotsFor(itemID) <= g.Player.MaxSlots) {
+			state.Items = removeOne(state.Items, itemID)
+			g.Player.Inventory = append(g.Player.Inventory, itemID)
+			g.Emit(ItemTaken, itemID, containerID)
+			taken++
+		}
+		if taken == 0 {
+			lines = append(lines, "You're carrying too much already.")
+			break
+		}
+		if taken == 1 {
+			lines = append(lines, fmt.Sprintf("You take the %s from the %s.", item.Name, box.Name))
+		} else {
+			lines = append(lines, fmt.Sprintf("You take %s from the %s.", g.stackName(itemID, taken), box.Name))
+		}
+	}
+	if len(lines) == 0 {
+		return g.refuse(fmt.Sprintf("The %s is empty.", box.Name))
+	}
+	return strings.Join(lines, "\n")
+}
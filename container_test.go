@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func satchelGame() (*GameState, *CommandProcessor) {
+	g := NewGameState()
+	g.Player.Location = "market_lane"
+	g.Player.Inventory = []string{"pearl", "pearl", "glyph_frag_1"}
+	return g, NewCommandProcessor()
+}
+
+func TestSatchelSlots(t *testing.T) {
+	g, c := satchelGame()
+	c.Execute(g, "take satchel")
+	before := g.InventorySlots()
+	c.Execute(g, "put 2 pearls in satchel, put fragment in satchel")
+	if got := len(g.container("satchel").Items); got != 3 {
+		t.Fatalf("satchel holds %v, want three items", g.container("satchel").Items)
+	}
+	if got := g.InventorySlots(); got != before {
+		t.Errorf("slots after packing = %d, want %d", got, before)
+	}
+	if !g.HasItem("glyph_frag_1") || countID(g.Carried(), "pearl") != 2 {
+		t.Errorf("packed items no longer count as carried: %v", g.Carried())
+	}
+	for len(g.Player.Inventory) < g.Player.MaxSlots-before+1 {
+		g.Player.Inventory = append(g.Player.Inventory, "rope")
+	}
+	c.Execute(g, "take pearl from satchel")
+	if countID(g.Player.Inventory, "pearl") != 1 {
+		t.Errorf("taking from a carried satchel was blocked with %d/%d slots", g.InventorySlots(), g.Player.MaxSlots)
+	}
+}
+
+func TestFullContainerSlots(t *testing.T) {
+	g, c := satchelGame()
+	g.container("satchel").Items = []string{"pearl", "pearl", "pearl", "pearl"}
+	for g.InventorySlots()+g.Items["satchel"].Slots < g.Player.MaxSlots {
+		g.Player.Inventory = append(g.Player.Inventory, "rope")
+	}
+	if got := c.Execute(g, "take satchel"); got[0] != "You're carrying too much already." {
+		t.Errorf("take full satchel = %q", got)
+	}
+	if g.InventorySlots() > g.Player.MaxSlots {
+		t.Errorf("slots %d over the %d cap", g.InventorySlots(), g.Player.MaxSlots)
+	}
+}
+
+func TestFullContainerCantLeave(t *testing.T) {
+	g, c := satchelGame()
+	g.Player.Inventory = append(g.Player.Inventory, "satchel")
+	g.container("satchel").Items = []string{"spice"}
+	if got := c.Execute(g, "sell satchel"); got[0] != "Empty the Leather Satchel first." {
+		t.Errorf("sell full satchel = %q", got)
+	}
+	if got := c.Execute(g, "drop satchel"); got[0] != "You drop the Leather Satchel, contents and all." {
+		t.Errorf("drop full satchel = %q", got)
+	}
+	if g.HasItem("spice") {
+		t.Errorf("spice still counts as carried after dropping the satchel")
+	}
+}
+
+func TestCraftFromSatchel(t *testing.T) {
+	g, _ := satchelGame()
+	g.Player.Inventory = []string{"satchel", "balm"}
+	g.container("satchel").Items = []string{"spice"}
+	g.Learned["fire_balm"] = true
+	g.Craft("fire balm")
+	if !contains(g.Player.Inventory, "fire_balm") || len(g.container("satchel").Items) != 0 {
+		t.Errorf("crafting left inventory %v and satchel %v", g.Player.Inventory, g.container("satchel").Items)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (g *GameState) contrabandIn(list []string) []string {
+	found := []string{}
+	for _, itemID := range list {
+		if item, ok := g.Items[itemID]; ok && item.Contraband {
+			found = append(found, itemID)
+		}
+	}
+	return found
+}
+
+func (g *GameState) confiscate(list []string, found []string) []string {
+	for _, itemID := range found {
+		list = removeOne(list, itemID)
+	}
+	return list
+}
+
+func (g *GameState) Checkpoint() {
+	found := g.contrabandIn(g.Player.Inventory)
+	if len(found) == 0 {
+		return
+	}
+	if g.InNavyUniform() {
+		g.AddLog(g.BluecoatSuspicion(), "event")
+	}
+	g.AddLog("A Bluecoat steps forward. 'Turn out your pockets, sailor.'", "event")
+	if g.SkillCheck("wits") {
+		g.AddLog("You palm the goods past the search. The Bluecoat waves you on.", "event")
+		return
+	}
+	g.Player.Inventory = g.confiscate(g.Player.Inventory, found)
+	g.AddWanted(1)
+	g.AddLog("They find "+g.ListItemNames(found)+" and confiscate it. Your name goes in the ledger.", "event")
+}
+
+func (g *GameState) SearchHold() {
+	found := g.contrabandIn(g.Ship.Hold)
+	found = append(found, g.contrabandIn(g.Player.Inventory)...)
+	if len(found) == 0 {
+		g.AddLog("The Bluecoats turn your ship inside out and find nothing.", "event")
+		return
+	}
+	g.Ship.Hold = g.confiscate(g.Ship.Hold, g.contrabandIn(g.Ship.Hold))
+	g.Player.Inventory = g.confiscate(g.Player.Inventory, g.contrabandIn(g.Player.Inventory))
+	g.AddWanted(2)
+	g.AddLog("The Bluecoats haul out "+g.ListItemNames(found)+". That will cost you.", "event")
+}
+
+func (g *GameState) honestRefusal(npcID string, itemID string) string {
+	npc := g.NPCs[npcID]
+	item := g.Items[itemID]
+	if g.SkillCheck("charm") {
+		return fmt.Sprintf("The %s eyes the %s and shakes their head. 'I run an honest stall.'", npc.Name, item.Name)
+	}
+	g.AddWanted(1)
+	return fmt.Sprintf("The %s refuses the %s and whistles for the watch. Word of it will spread.", npc.Name, item.Name)
+}
+
+func (g *GameState) Hide(name string) string {
+	if g.Room().Island != "Ship" {
+		return g.refuse("You need to be aboard to reach the hidden compartment.")
+	}
+	if g.Ship.HiddenSlots == 0 {
+		return g.refuse("Your ship has no hidden compartment. A fence might know a shipwright who can build one.")
+	}
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	if refusal := g.notEmpty(itemID); refusal != "" {
+		return g.refuse(refusal)
+	}
+	item := g.Items[itemID]
+	used := 0
+	for _, hiddenID := range g.Ship.Hidden {
+		used += g.Items[hiddenID].Slots
+	}
+	if used+item.Slots > g.Ship.HiddenSlots {
+		return g.refuse("The compartment is full.")
+	}
+	g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+	g.Ship.Hidden = append(g.Ship.Hidden, itemID)
+	return fmt.Sprintf("You slide the %s behind the false bulkhead.", item.Name)
+}
+
+func (g *GameState) Unhide(name string) string {
+	if g.Room().Island != "Ship" {
+		return g.refuse("You need to be aboard to reach the hidden compartment.")
+	}
+	itemID := g.FindItem(name, g.Ship.Hidden)
+	if itemID == "" {
+		return g.refuse("That isn't in the compartment.")
+	}
+	item := g.Items[itemID]
+	if g.InventorySlots()+g.slotsFor(itemID) > g.Player.MaxSlots {
+		return g.refuse("You're carrying too much already.")
+	}
+	g.Ship.Hidden = removeOne(g.Ship.Hidden, itemID)
+	g.Player.Inventory = append(g.Player.Inventory, itemID)
+	return fmt.Sprintf("You retrieve the %s from the compartment.", item.Name)
+}
+
+func (g *GameState) HiddenReport() string {
+	if g.Ship.HiddenSlots == 0 {
+		return ""
+	}
+	contents := "empty"
+	if len(g.Ship.Hidden) > 0 {
+		contents = g.ListItemNames(g.Ship.Hidden)
+	}
+	return strings.Join([]string{"Compartment:", contents}, " ")
+}
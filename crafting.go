@@ -0,0 +1,200 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed assets/recipes.json
+var builtinRecipes []byte
+
+type Recipe struct {
+	ID      string
+	Name    string
+	Inputs  []string
+	Tools   []string
+	Output  string
+	Count   int
+	Station string
+	Known   bool
+	Flag    string
+	Message string
+}
+
+func Stations() map[string]string {
+	return map[string]string{
+		"forge":    "a forge",
+		"shipyard": "a shipwright's yard",
+	}
+}
+
+func LoadRecipes() ([]Recipe, error) {
+	var recipes []Recipe
+	err := loadContent("recipes.json", builtinRecipes, &recipes)
+	return recipes, err
+}
+
+func (g *GameState) InitRecipes() {
+	g.Learned = map[string]bool{}
+	for _, recipe := range g.Recipes {
+		if recipe.Known {
+			g.Learned[recipe.ID] = true
+		}
+	}
+}
+
+func (g *GameState) recipeUses(recipe Recipe, itemID string) int {
+	return countID(recipe.Inputs, itemID) + countID(recipe.Tools, itemID)
+}
+
+func (g *GameState) recipeMissing(recipe Recipe) string {
+	missing := []string{}
+	for _, itemID := range stackIDs(append(append([]string{}, recipe.Inputs...), recipe.Tools...)) {
+		if countID(g.Carried(), itemID) < g.recipeUses(recipe, itemID) {
+			missing = append(missing, itemID)
+		}
+	}
+	if len(missing) > 0 {
+		return "You'll also need: " + g.ListItemNames(missing) + "."
+	}
+	return g.recipeStation(recipe)
+}
+
+func (g *GameState) recipeStation(recipe Recipe) string {
+	if recipe.Station != "" && !contains(g.Room().Tags, recipe.Station) {
+		return fmt.Sprintf("You need %s to make the %s.", Stations()[recipe.Station], recipe.Name)
+	}
+	return ""
+}
+
+func (g *GameState) learn(recipe Recipe) string {
+	if g.Learned[recipe.ID] {
+		return ""
+	}
+	g.Learned[recipe.ID] = true
+	return " (Added to your recipe book.)"
+}
+
+func (g *GameState) recipeLine(recipe Recipe) string {
+	names := []string{}
+	for _, itemID := range stackIDs(recipe.Inputs) {
+		names = append(names, g.stackName(itemID, countID(recipe.Inputs, itemID)))
+	}
+	line := recipe.Name + ": " + strings.Join(names, " + ")
+	if len(recipe.Tools) > 0 {
+		line += " (using " + g.ListItemNames(recipe.Tools) + ")"
+	}
+	if recipe.Station != "" {
+		line += " at " + Stations()[recipe.Station]
+	}
+	if g.recipeMissing(recipe) == "" {
+		line += " [ready]"
+	}
+	return line
+}
+
+func (g *GameState) RecipeBook() string {
+	lines := []string{"Recipe book:"}
+	unknown := 0
+	for _, recipe := range g.Recipes {
+		if !g.Learned[recipe.ID] {
+			unknown++
+			continue
+		}
+		lines = append(lines, "- "+g.recipeLine(recipe))
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "(empty)")
+	}
+	if unknown > 0 {
+		lines = append(lines, fmt.Sprintf("%d more to discover. Try COMBINE <item> WITH <item>.", unknown))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) ReadyRecipes() []Recipe {
+	ready := []Recipe{}
+	for _, recipe := range g.Recipes {
+		if g.Learned[recipe.ID] && g.recipeMissing(recipe) == "" {
+			ready = append(ready, recipe)
+		}
+	}
+	return ready
+}
+
+func (g *GameState) craft(recipe Recipe) string {
+	if missing := g.recipeMissing(recipe); missing != "" {
+		if missing == g.recipeStation(recipe) {
+			missing += g.learn(recipe)
+		}
+		return g.refuse(missing)
+	}
+	for _, itemID := range recipe.Inputs {
+		g.removeCarried(itemID)
+	}
+	g.checkEquipped()
+	for i := 0; i < max(1, recipe.Count); i++ {
+		g.Player.Inventory = append(g.Player.Inventory, recipe.Output)
+	}
+	message := recipe.Message + g.learn(recipe)
+	if recipe.Flag != "" {
+		g.SetFlag(recipe.Flag)
+	}
+	g.Emit(ItemCrafted, recipe.Output, recipe.ID)
+	return message
+}
+
+func (g *GameState) Combine(first string, second string) string {
+	firstID := g.FindItem(first, g.Player.Inventory)
+	secondID := g.FindItem(second, g.Player.Inventory)
+	if firstID == "" || secondID == "" {
+		return g.refuse("You need both in hand to combine them.")
+	}
+	if firstID == secondID && countID(g.Player.Inventory, firstID) < 2 {
+		return g.refuse("You only have the one.")
+	}
+	var found *Recipe
+	for i, recipe := range g.Recipes {
+		if g.recipeUses(recipe, firstID) == 0 || g.recipeUses(recipe, secondID) == 0 {
+			continue
+		}
+		if firstID == secondID && g.recipeUses(recipe, firstID) < 2 {
+			continue
+		}
+		if found == nil || g.recipeMissing(recipe) == "" {
+			found = &g.Recipes[i]
+		}
+	}
+	if found == nil {
+		return g.refuse(fmt.Sprintf("You fiddle with the %s and the %s, but nothing useful comes of it.", g.Items[firstID].Name, g.Items[secondID].Name))
+	}
+	return g.craft(*found)
+}
+
+func (g *GameState) Craft(name string) string {
+	if name == "" {
+		return g.RecipeBook()
+	}
+	outputs := []string{}
+	for _, recipe := range g.Recipes {
+		if !g.Learned[recipe.ID] {
+			continue
+		}
+		if strings.EqualFold(recipe.Name, name) {
+			return g.craft(recipe)
+		}
+		outputs = append(outputs, recipe.Output)
+	}
+	matches := g.MatchNames(name, outputs)
+	if len(matches) == 0 {
+		return g.refuse("You don't know how to make that. Try COMBINE <item> WITH <item> to experiment.")
+	}
+	var best Recipe
+	for _, recipe := range g.Recipes {
+		if g.Learned[recipe.ID] && recipe.Output == matches[0] && (best.ID == "" || g.recipeMissing(recipe) == "") {
+			best = recipe
+		}
+	}
+	return g.craft(best)
+}
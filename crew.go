@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+type CrewRole struct {
+	ID          string
+	Name        string
+	CombatBonus int
+	HealBonus   int
+	RiskMod     int
+	SkillStat   string
+	SkillBonus  int
+}
+
+type CompanionDef struct {
+	NPC        string
+	Role       string
+	Wage       int
+	Home       string
+	NeedsQuest string
+	NeedsMood  string
+	QuitMorale int
+	Comments   map[string]string
+}
+
+func CrewRoles() map[string]CrewRole {
+	return map[string]CrewRole{
+		"medic":     {ID: "medic", Name: "Medic", HealBonus: 3},
+		"gunner":    {ID: "gunner", Name: "Gunner", CombatBonus: 2},
+		"navigator": {ID: "navigator", Name: "Navigator", RiskMod: -1},
+		"lookout":   {ID: "lookout", Name: "Lookout", SkillStat: "wits", SkillBonus: 1},
+	}
+}
+
+func CompanionTable() map[string]CompanionDef {
+	return map[string]CompanionDef{
+		"cook": {NPC: "cook", Role: "gunner", Wage: 2, Home: "ship_deck", QuitMorale: -4, Comments: map[string]string{
+			"combat": "That's how you tenderise a problem!",
+			"voyage": "Rations are holding. For now.",
+			"hungry": "I can't cook air, Captain.",
+		}},
+		"dockhand": {NPC: "dockhand", Role: "lookout", Wage: 3, Home: "dock", NeedsQuest: "dockhand", QuitMorale: -2, Comments: map[string]string{
+			"combat": "Arm's good as new. So's my swing.",
+			"voyage": "Sails on the horizon. Eyes open, everyone.",
+			"storm":  "Lash everything down!",
+		}},
+		"herbalist": {NPC: "herbalist", Role: "medic", Wage: 5, Home: "jungle_grove", NeedsMood: "friendly", QuitMorale: -3, Comments: map[string]string{
+			"combat": "Hold still. This will sting.",
+			"voyage": "Salt air. Good for the lungs, bad for the leaves.",
+			"storm":  "The jungle warned me about this sky.",
+		}},
+		"gadgeteer": {NPC: "gadgeteer", Role: "navigator", Wage: 4, Home: "market_lane", NeedsQuest: "gadgeteer", QuitMorale: -2, Comments: map[string]string{
+			"combat": "I'll file that under field testing.",
+			"voyage": "My lens says we're on course. Mostly.",
+			"storm":  "Fascinating pressure readings! Also terrifying.",
+		}},
+	}
+}
+
+func (g *GameState) Recruit(name string) string {
+	room := g.Room()
+	npcID := g.FindNPC(name, room.NPCs)
+	if npcID == "" {
+		return g.refuse("No one like that is here.")
+	}
+	npc := g.NPCs[npcID]
+	def, ok := CompanionTable()[npcID]
+	if !ok {
+		return g.refuse(fmt.Sprintf("The %s has no interest in joining your crew.", npc.Name))
+	}
+	if contains(g.Companions, npcID) {
+		return fmt.Sprintf("The %s is already part of your crew.", npc.Name)
+	}
+	if def.NeedsQuest != "" {
+		if quest, ok := g.Quests[def.NeedsQuest]; ok && !quest.Done {
+			return g.refuse(fmt.Sprintf("The %s wants proof you're worth sailing with first.", npc.Name))
+		}
+	}
+	if def.NeedsMood != "" && g.Mood(npcID) != def.NeedsMood {
+		return g.refuse(fmt.Sprintf("The %s doesn't trust you enough yet.", npc.Name))
+	}
+	if g.Money < def.Wage {
+		return g.refuse("You can't even cover the first day's wage.")
+	}
+	g.Money -= def.Wage
+	room.NPCs = removeID(room.NPCs, npcID)
+	if deck := g.Rooms["ship_deck"]; deck != nil && !contains(deck.NPCs, npcID) {
+		deck.NPCs = append(deck.NPCs, npcID)
+	}
+	g.Companions = append(g.Companions, npcID)
+	g.Ship.Crew++
+	role := CrewRoles()[def.Role]
+	return fmt.Sprintf("The %s signs on as your %s for %d coins a day.", npc.Name, strings.ToLower(role.Name), def.Wage)
+}
+
+func (g *GameState) Dismiss(name string) string {
+	npcID := g.FindNPC(name, g.Companions)
+	if npcID == "" {
+		return g.refuse("No one by that name sails with you.")
+	}
+	g.leaveCrew(npcID)
+	return fmt.Sprintf("The %s shoulders their bag and heads home.", g.NPCs[npcID].Name)
+}
+
+func (g *GameState) leaveCrew(npcID string) {
+	def := CompanionTable()[npcID]
+	g.Companions = removeID(g.Companions, npcID)
+	g.Ship.Crew = max(0, g.Ship.Crew-1)
+	if def.Home == "ship_deck" {
+		return
+	}
+	if deck := g.Rooms["ship_deck"]; deck != nil {
+		deck.NPCs = removeID(deck.NPCs, npcID)
+	}
+	if home := g.Rooms[def.Home]; home != nil && !contains(home.NPCs, npcID) {
+		home.NPCs = append(home.NPCs, npcID)
+	}
+}
+
+func (g *GameState) CrewReport() string {
+	if len(g.Companions) == 0 {
+		return "No companions have signed on yet. Try RECRUIT <npc>."
+	}
+	roles := CrewRoles()
+	lines := []string{"Companions:"}
+	for _, npcID := range g.Companions {
+		def := CompanionTable()[npcID]
+		lines = append(lines, fmt.Sprintf("- %s, %s (%d coins/day)", g.NPCs[npcID].Name, roles[def.Role].Name, def.Wage))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) PayCrew() {
+	if len(g.Companions) == 0 {
+		return
+	}
+	total := 0
+	for _, npcID := range g.Companions {
+		total += CompanionTable()[npcID].Wage
+	}
+	if g.Money < total {
+		g.Morale -= 2
+		g.AddLog(fmt.Sprintf("You can't cover %d coins in wages. The crew mutters darkly.", total), "event")
+	} else {
+		g.Money -= total
+		g.AddLog(fmt.Sprintf("You pay the crew %d coins in wages.", total), "event")
+	}
+}
+
+func (g *GameState) CheckCrewMorale() {
+	for _, npcID := range append([]string{}, g.Companions...) {
+		def := CompanionTable()[npcID]
+		if g.Morale > def.QuitMorale {
+			continue
+		}
+		g.leaveCrew(npcID)
+		g.AddLog(fmt.Sprintf("The %s has had enough and quits your crew.", g.NPCs[npcID].Name), "event")
+	}
+}
+
+func (g *GameState) CrewComment(kind string) {
+	lines := []string{}
+	for _, npcID := range g.Companions {
+		if line, ok := CompanionTable()[npcID].Comments[kind]; ok {
+			lines = append(lines, g.NPCs[npcID].Name+": '"+line+"'")
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	g.AddLog(lines[rand.Intn(len(lines))], "crew")
+}
+
+func (g *GameState) crewRoles() []CrewRole {
+	roles := []CrewRole{}
+	table := CrewRoles()
+	for _, npcID := range g.Companions {
+		roles = append(roles, table[CompanionTable()[npcID].Role])
+	}
+	return roles
+}
+
+func (g *GameState) CrewCombatBonus() int {
+	bonus := 0
+	for _, role := range g.crewRoles() {
+		bonus += role.CombatBonus
+	}
+	return bonus
+}
+
+func (g *GameState) CrewHealBonus() int {
+	bonus := 0
+	for _, role := range g.crewRoles() {
+		bonus += role.HealBonus
+	}
+	return bonus
+}
+
+func (g *GameState) CrewRiskMod() int {
+	mod := 0
+	for _, role := range g.crewRoles() {
+		mod += role.RiskMod
+	}
+	return mod
+}
+
+func (g *GameState) CrewSkillBonus(stat string) int {
+	bonus := 0
+	for _, role := range g.crewRoles() {
+		if role.SkillStat == stat {
+			bonus += role.SkillBonus
+		}
+	}
+	return bonus
+}
@@ -12,12 +12,16 @@ type Item struct {
 }
 
 type NPC struct {
-	ID          string
-	Name        string
-	Desc        string
-	Talk        string
-	Disposition string
-	Shop        []string
+	ID           string
+	Name         string
+	Desc         string
+	Talk         string
+	Disposition  string
+	Shop         []string
+	Fence        bool
+	Faction      string
+	TalkFriendly string
+	TalkWary     string
 }
 
 type Enemy struct {
@@ -29,7 +33,9 @@ type Enemy struct {
 	MaxDamage  int
 	WantedGain int
 	FleeChance float64
+	Venom      float64
 	IsBoss     bool
+	Faction    string
 }
 
 type Room struct {
@@ -48,12 +54,14 @@ type Room struct {
 }
 
 type Quest struct {
-	ID      string
-	Name    string
-	Desc    string
-	Active  bool
-	Done    bool
-	Outcome string
+	ID        string
+	Name      string
+	Desc      string
+	Active    bool
+	Done      bool
+	Outcome   string
+	Faction   string
+	RepReward int
 }
 
 type Island struct {
@@ -90,77 +98,99 @@ func WorldData() (map[string]*Room, map[string]*Item, map[string]*NPC, map[strin
 		"treasure_core": {ID: "treasure_core", Name: "Treasure Coordinate Core", Desc: "The legendary coordinates glow within.", Type: "quest", Slots: 1, Value: 0},
 		"map_scrap":     {ID: "map_scrap", Name: "Map Scrap", Desc: "A torn scrap pointing inland.", Type: "lore", Slots: 1, Value: 8},
 		"storm_lantern": {ID: "storm_lantern", Name: "Storm Lantern", Desc: "Refuses to go out, even in heavy rain.", Type: "tool", Slots: 1, Value: 20},
+		"fisher_cloak":  {ID: "fisher_cloak", Name: "Fisher's Cloak", Desc: "Patched oilskin that smells of bait. Nobody looks twice.", Type: "disguise", Slots: 1, Value: 18},
+		"deck_coat":     {ID: "deck_coat", Name: "Deckhand's Coat", Desc: "Salt-stiff wool with brass buttons. Close enough to regulation.", Type: "clothing", Slots: 1, Value: 12},
+		"navy_uniform":  {ID: "navy_uniform", Name: "Bluecoat Uniform", Desc: "Pressed, pinned and almost convincing.", Type: "disguise", Slots: 1, Value: 45},
 		"smoke_bomb":    {ID: "smoke_bomb", Name: "Smoke Bomb", Desc: "Great for exits. Also for excuses.", Type: "tool", Slots: 1, Value: 25},
 		"sea_boots":     {ID: "sea_boots", Name: "Sea Boots", Desc: "Boots with weighted soles and great grip.", Type: "tool", Slots: 1, Value: 18},
 		"pearl":         {ID: "pearl", Name: "Moon Pearl", Desc: "A luminous pearl with a cold glow.", Type: "trade", Slots: 1, Value: 45},
 		"dock_pass":     {ID: "dock_pass", Name: "Dock Pass", Desc: "Lets you slip past port checks.", Type: "quest", Slots: 1, Value: 0},
 		"repair_kit":    {ID: "repair_kit", Name: "Repair Kit", Desc: "Patchwork supplies for ship or gear.", Type: "tool", Slots: 1, Value: 20},
+		"hull_plates":   {ID: "hull_plates", Name: "Reinforced Hull Plates", Desc: "Oak and iron plating that shrugs off coral.", Type: "upgrade", Slots: 2, Value: 60},
+		"smuggler_hold": {ID: "smuggler_hold", Name: "Smuggler's Compartment", Desc: "Plans and panels for a false bulkhead.", Type: "upgrade", Slots: 2, Value: 70},
+		"swift_sails":   {ID: "swift_sails", Name: "Swift Sails", Desc: "Cut from storm silk. Catches every breath of wind.", Type: "upgrade", Slots: 2, Value: 55},
 		"gale_fruit":    {ID: "gale_fruit", Name: "Gale Gale Fruit", Desc: "Swirls like a storm cloud.", Type: "fruit", Slots: 1, Value: 0, Fruit: true},
 		"stone_fruit":   {ID: "stone_fruit", Name: "Stonewave Fruit", Desc: "Rumbles softly, like distant thunder.", Type: "fruit", Slots: 1, Value: 0, Fruit: true},
 		"spark_fruit":   {ID: "spark_fruit", Name: "Sparkstep Fruit", Desc: "A crackling fruit that smells of rain.", Type: "fruit", Slots: 1, Value: 0, Fruit: true},
+		"sea_chest":     {ID: "sea_chest", Name: "Sea Chest", Desc: "Iron-banded oak with your predecessor's initials burned into the lid.", Type: "container", Slots: 6, Value: 0},
+		"chest_key":     {ID: "chest_key", Name: "Small Brass Key", Desc: "A little key on a frayed cord. It smells of the cook's stew.", Type: "tool", Slots: 0, Value: 2},
+		"satchel":       {ID: "satchel", Name: "Leather Satchel", Desc: "A shoulder bag with more pockets than sense. Holds four slots' worth.", Type: "container", Slots: 1, Value: 20},
+		"forge_crates":  {ID: "forge_crates", Name: "Smuggler's Crates", Desc: "Crates stamped 'NAILS' that clink far too softly to hold nails.", Type: "container", Slots: 8, Value: 0},
+		"hardtack":      {ID: "hardtack", Name: "Ship's Biscuit", Desc: "Hardtack, baked twice and dated never. Keeps forever.", Type: "consumable", Slots: 1, Value: 4},
+		"torch":         {ID: "torch", Name: "Pitch Torch", Desc: "A resin-soaked stick. Burns bright, smokes worse, lasts about three hours.", Type: "tool", Slots: 1, Value: 5},
+		"lamp_oil":      {ID: "lamp_oil", Name: "Lamp Oil", Desc: "A stoppered flask of whale oil. Enough to fill a lantern.", Type: "tool", Slots: 1, Value: 6},
+		"buried_cache":  {ID: "buried_cache", Name: "Buried Cache", Desc: "An iron-banded box half dug out of the black sand.", Type: "container", Slots: 6, Value: 0},
+		"grapple_line":  {ID: "grapple_line", Name: "Grapple Line", Desc: "A hook on a good length of rope. Made for walls that don't want you.", Type: "tool", Slots: 1, Value: 45},
+		"fire_balm":     {ID: "fire_balm", Name: "Fire Balm", Desc: "Herbal balm cut with island spice. Stings, then soothes.", Type: "consumable", Slots: 1, Value: 40},
+		"marked_chart":  {ID: "marked_chart", Name: "Marked Chart", Desc: "The Wild Current chart with the map scrap pinned in place. X marks Ember Isle.", Type: "quest", Slots: 1, Value: 50},
 	}
 
 	npcs := map[string]*NPC{
 		"cook":       {ID: "cook", Name: "Ship Cook", Desc: "A cook with a ladle like a sword.", Talk: "Keep your hands busy and your belly fuller.", Disposition: "friendly"},
 		"dockhand":   {ID: "dockhand", Name: "Dockhand", Desc: "A dockhand with a bandaged arm.", Talk: "Got any supplies? This arm's itching.", Disposition: "neutral"},
-		"officer":    {ID: "officer", Name: "Bluecoat Officer", Desc: "A stern officer guarding the gate.", Talk: "Outpost access is restricted.", Disposition: "hostile"},
-		"bartender":  {ID: "bartender", Name: "Tavern Bartender", Desc: "Polishing a mug with style.", Talk: "Rum loosens tongues and contracts.", Disposition: "neutral", Shop: []string{"rum", "smoke_bomb"}},
-		"broker":     {ID: "broker", Name: "Shady Broker", Desc: "A broker with a grin that costs extra.", Talk: "Secrets are cheaper than anchors.", Disposition: "neutral", Shop: []string{"stone_key", "cipher_lens"}},
-		"gadgeteer":  {ID: "gadgeteer", Name: "Gadgeteer", Desc: "Covered in soot and glitter.", Talk: "Spice makes my lenses sing.", Disposition: "neutral", Shop: []string{"gadget_gull", "storm_lantern"}},
-		"herbalist":  {ID: "herbalist", Name: "Herbalist", Desc: "Sorting leaves with a smile.", Talk: "The jungle speaks if you listen.", Disposition: "friendly", Shop: []string{"balm", "medkit"}},
-		"librarian":  {ID: "librarian", Name: "Mist Librarian", Desc: "A librarian with fog in her hair.", Talk: "Knowledge is safer when shared.", Disposition: "friendly"},
-		"shipwright": {ID: "shipwright", Name: "Shipwright", Desc: "Wearing a belt of tools and sea salt.", Talk: "Fix the hull, fix the fate.", Disposition: "neutral", Shop: []string{"repair_kit", "sea_boots"}},
-		"rival":      {ID: "rival", Name: "Rival Pirate", Desc: "A flashy pirate with a louder hat.", Talk: "The Wild Current has room for one legend.", Disposition: "hostile"},
-		"priest":     {ID: "priest", Name: "Shrine Keeper", Desc: "Keeper of the storm shrine.", Talk: "Offerings calm the sky.", Disposition: "neutral"},
+		"officer":    {ID: "officer", Name: "Bluecoat Officer", Desc: "A stern officer guarding the gate.", Talk: "Outpost access is restricted.", Disposition: "hostile", Faction: "navy", TalkFriendly: "Carry on, Captain. Keep your nose clean.", TalkWary: "One more stunt and you'll be swinging from the yardarm."},
+		"bartender":  {ID: "bartender", Name: "Tavern Bartender", Desc: "Polishing a mug with style.", Talk: "Rum loosens tongues and contracts.", Disposition: "neutral", Shop: []string{"rum", "smoke_bomb", "hardtack"}},
+		"broker":     {ID: "broker", Name: "Shady Broker", Desc: "A broker with a grin that costs extra.", Talk: "Secrets are cheaper than anchors.", Disposition: "neutral", Shop: []string{"smuggler_hold", "bribe", "navy_uniform"}, Fence: true, Faction: "smugglers", TalkFriendly: "For you, friend, the good stuff comes out from under the counter.", TalkWary: "I don't deal with snitches. Walk away."},
+		"gadgeteer":  {ID: "gadgeteer", Name: "Gadgeteer", Desc: "Covered in soot and glitter.", Talk: "Spice makes my lenses sing.", Disposition: "neutral", Shop: []string{"gadget_gull", "storm_lantern", "lamp_oil", "spice", "bribe"}},
+		"herbalist":  {ID: "herbalist", Name: "Herbalist", Desc: "Sorting leaves with a smile.", Talk: "The jungle speaks if you listen.", Disposition: "friendly", Shop: []string{"balm", "medkit"}, Faction: "villagers", TalkWary: "The village has heard what you did. Buy what you need and go."},
+		"librarian":  {ID: "librarian", Name: "Mist Librarian", Desc: "A librarian with fog in her hair.", Talk: "Knowledge is safer when shared.", Disposition: "friendly", Faction: "scholars", TalkFriendly: "Ah, our patron of the glyphs. The stacks are yours.", TalkWary: "The library is closed to looters."},
+		"shipwright": {ID: "shipwright", Name: "Shipwright", Desc: "Wearing a belt of tools and sea salt.", Talk: "Fix the hull, fix the fate.", Disposition: "neutral", Shop: []string{"repair_kit", "sea_boots", "hull_plates", "swift_sails", "torch"}},
+		"rival":      {ID: "rival", Name: "Rival Pirate", Desc: "A flashy pirate with a louder hat.", Talk: "The Wild Current has room for one legend.", Disposition: "hostile", Faction: "pirates"},
+		"priest":     {ID: "priest", Name: "Shrine Keeper", Desc: "Keeper of the storm shrine.", Talk: "Offerings calm the sky.", Disposition: "neutral", Faction: "villagers", TalkFriendly: "The storm spirits know your name kindly, Captain."},
+		"trader":     {ID: "trader", Name: "Ember Trader", Desc: "A trader with soot-black fingers and a fat ledger.", Talk: "Pearls are cheap here. Sail them north and get rich.", Disposition: "neutral", Shop: []string{"pearl", "spice", "balm", "hardtack", "torch"}, Faction: "villagers"},
+		"vendor":     {ID: "vendor", Name: "Lantern Vendor", Desc: "A vendor whose stall glows blue in the fog.", Talk: "Spice is scarce on Mist Isle. I pay well for it.", Disposition: "neutral", Shop: []string{"smoke_bomb", "pearl", "rum", "flintlock", "fisher_cloak"}, Fence: true, Faction: "smugglers"},
 	}
 
 	enemies := map[string]*Enemy{
-		"reef_beast":   {ID: "reef_beast", Name: "Reef Beast", Desc: "A coral-covered brute with too many teeth.", HP: 14, MinDamage: 2, MaxDamage: 5, WantedGain: 0, FleeChance: 0.1},
-		"navy_patrol":  {ID: "navy_patrol", Name: "Bluecoat Patrol", Desc: "Two Bluecoats with nets and attitude.", HP: 12, MinDamage: 2, MaxDamage: 4, WantedGain: 2, FleeChance: 0.2},
-		"smuggler":     {ID: "smuggler", Name: "Spice Smuggler", Desc: "A smuggler guarding hidden crates.", HP: 10, MinDamage: 1, MaxDamage: 4, WantedGain: 1, FleeChance: 0.3},
-		"rival_pirate": {ID: "rival_pirate", Name: "Rival Pirate", Desc: "A rival captain with a sharp grin.", HP: 16, MinDamage: 3, MaxDamage: 6, WantedGain: 2, FleeChance: 0.05, IsBoss: true},
-		"navy_captain": {ID: "navy_captain", Name: "Bluecoat Captain", Desc: "A Navy captain with a polished saber.", HP: 18, MinDamage: 3, MaxDamage: 6, WantedGain: 3, FleeChance: 0.1, IsBoss: true},
+		"reef_beast":   {ID: "reef_beast", Name: "Reef Beast", Desc: "A coral-covered brute with too many teeth.", HP: 14, MinDamage: 2, MaxDamage: 5, WantedGain: 0, FleeChance: 0.1, Venom: 0.3},
+		"navy_patrol":  {ID: "navy_patrol", Name: "Bluecoat Patrol", Desc: "Two Bluecoats with nets and attitude.", HP: 12, MinDamage: 2, MaxDamage: 4, WantedGain: 2, FleeChance: 0.2, Faction: "navy"},
+		"smuggler":     {ID: "smuggler", Name: "Spice Smuggler", Desc: "A smuggler guarding hidden crates.", HP: 10, MinDamage: 1, MaxDamage: 4, WantedGain: 1, FleeChance: 0.3, Faction: "smugglers"},
+		"rival_pirate": {ID: "rival_pirate", Name: "Rival Pirate", Desc: "A rival captain with a sharp grin.", HP: 16, MinDamage: 3, MaxDamage: 6, WantedGain: 2, FleeChance: 0.05, IsBoss: true, Faction: "pirates"},
+		"raider":       {ID: "raider", Name: "Pirate Raider", Desc: "A rival's deckhand with a grudge and a boarding axe.", HP: 11, MinDamage: 2, MaxDamage: 5, WantedGain: 1, FleeChance: 0.25, Faction: "pirates"},
+		"navy_captain": {ID: "navy_captain", Name: "Bluecoat Captain", Desc: "A Navy captain with a polished saber.", HP: 18, MinDamage: 3, MaxDamage: 6, WantedGain: 3, FleeChance: 0.1, IsBoss: true, Faction: "navy"},
 	}
 
 	rooms := map[string]*Room{
-		"ship_deck":     {ID: "ship_deck", Name: "Rookie Deck", Island: "Ship", Desc: "Your scrappy ship bobs in the harbor. A note says: 'Try LOOK, INVENTORY, then GO NORTH.'", Exits: map[string]string{"north": "dock", "south": "ship_cabin"}, Items: []string{"rope", "flare"}, NPCs: []string{"cook"}, Tags: []string{"dock"}, CoordX: 2, CoordY: 2},
-		"ship_cabin":    {ID: "ship_cabin", Name: "Captain's Cabin", Island: "Ship", Desc: "A cramped cabin with maps and ambition.", Exits: map[string]string{"north": "ship_deck"}, Items: []string{"nav_log", "compass"}, Tags: []string{}, CoordX: 2, CoordY: 3},
-		"dock":          {ID: "dock", Name: "Harbor Dock", Island: "Harbor Isle", Desc: "Workers shout over gulls. The island town sprawls north.", Exits: map[string]string{"south": "ship_deck", "north": "town_square", "east": "market_lane", "west": "reef_shallows"}, Items: []string{"grappling"}, NPCs: []string{"dockhand"}, Tags: []string{"dock"}, CoordX: 2, CoordY: 1},
+		"ship_deck":     {ID: "ship_deck", Name: "Rookie Deck", Island: "Ship", Desc: "Your scrappy ship bobs in the harbor. A note says: 'Try LOOK, INVENTORY, then GO NORTH.'", Exits: map[string]string{"north": "dock", "south": "ship_cabin"}, Items: []string{"rope", "flare", "chest_key"}, NPCs: []string{"cook"}, Tags: []string{"dock"}, CoordX: 2, CoordY: 2},
+		"ship_cabin":    {ID: "ship_cabin", Name: "Captain's Cabin", Island: "Ship", Desc: "A cramped cabin with maps and ambition.", Exits: map[string]string{"north": "ship_deck"}, Items: []string{"nav_log", "compass", "sea_chest"}, Tags: []string{"lit"}, CoordX: 2, CoordY: 3},
+		"dock":          {ID: "dock", Name: "Harbor Dock", Island: "Harbor Isle", Desc: "Workers shout over gulls. The island town sprawls north.", Exits: map[string]string{"south": "ship_deck", "north": "town_square", "east": "market_lane", "west": "reef_shallows"}, Items: []string{"grappling"}, NPCs: []string{"dockhand"}, Tags: []string{"dock", "lit"}, CoordX: 2, CoordY: 1},
 		"town_square":   {ID: "town_square", Name: "Town Square", Island: "Harbor Isle", Desc: "A plaza of stalls and gossip. A Bluecoat watches the gate.", Exits: map[string]string{"south": "dock", "east": "tavern", "west": "market_lane", "north": "navy_gate", "northeast": "shipyard"}, Items: []string{"bounty_poster"}, NPCs: []string{"officer"}, Tags: []string{}, CoordX: 2, CoordY: 0},
-		"tavern":        {ID: "tavern", Name: "Tidal Tavern", Island: "Harbor Isle", Desc: "Sticky tables and loud rumors.", Exits: map[string]string{"west": "town_square"}, Items: []string{"rum"}, NPCs: []string{"bartender", "broker"}, Tags: []string{"shop"}, CoordX: 3, CoordY: 0},
-		"market_lane":   {ID: "market_lane", Name: "Market Lane", Island: "Harbor Isle", Desc: "Lanterns sway over traders hawking gizmos.", Exits: map[string]string{"east": "town_square", "south": "dock", "west": "reef_shallows", "north": "jungle_path"}, Items: []string{"spice", "bribe", "gadget_gull"}, NPCs: []string{"gadgeteer"}, Tags: []string{"shop"}, CoordX: 1, CoordY: 0},
-		"navy_gate":     {ID: "navy_gate", Name: "Bluecoat Gate", Island: "Harbor Isle", Desc: "A guarded gate leading to the Navy outpost.", Exits: map[string]string{"south": "town_square", "north": "navy_outpost"}, Items: []string{}, NPCs: []string{"officer"}, Tags: []string{}, CoordX: 2, CoordY: -1},
+		"tavern":        {ID: "tavern", Name: "Tidal Tavern", Island: "Harbor Isle", Desc: "Sticky tables and loud rumors.", Exits: map[string]string{"west": "town_square"}, Items: []string{}, NPCs: []string{"bartender", "broker"}, Tags: []string{"shop", "lit"}, CoordX: 3, CoordY: 0},
+		"market_lane":   {ID: "market_lane", Name: "Market Lane", Island: "Harbor Isle", Desc: "Lanterns sway over traders hawking gizmos.", Exits: map[string]string{"east": "town_square", "south": "dock", "west": "reef_shallows", "north": "jungle_path"}, Items: []string{"satchel"}, NPCs: []string{"gadgeteer"}, Tags: []string{"shop", "lit"}, CoordX: 1, CoordY: 0},
+		"navy_gate":     {ID: "navy_gate", Name: "Bluecoat Gate", Island: "Harbor Isle", Desc: "A guarded gate leading to the Navy outpost.", Exits: map[string]string{"south": "town_square", "north": "navy_outpost"}, Items: []string{}, NPCs: []string{"officer"}, Tags: []string{"checkpoint"}, CoordX: 2, CoordY: -1},
 		"navy_outpost":  {ID: "navy_outpost", Name: "Bluecoat Outpost", Island: "Navy Bastion", Desc: "A stiff post of polished boots and judgment.", Exits: map[string]string{"south": "navy_gate"}, Items: []string{"navy_badge", "flintlock"}, Enemies: []string{"navy_captain"}, Tags: []string{"danger"}, CoordX: 2, CoordY: -2},
-		"shipyard":      {ID: "shipyard", Name: "Shipyard", Island: "Harbor Isle", Desc: "Hull frames and resin scents fill the air.", Exits: map[string]string{"southwest": "town_square"}, Items: []string{"repair_kit", "sea_boots"}, NPCs: []string{"shipwright"}, Tags: []string{"shop"}, CoordX: 3, CoordY: -1},
+		"shipyard":      {ID: "shipyard", Name: "Shipyard", Island: "Harbor Isle", Desc: "Hull frames and resin scents fill the air.", Exits: map[string]string{"southwest": "town_square"}, Items: []string{"deck_coat"}, NPCs: []string{"shipwright"}, Tags: []string{"shop", "shipyard"}, CoordX: 3, CoordY: -1},
 		"reef_shallows": {ID: "reef_shallows", Name: "Reef Shallows", Island: "Harbor Isle", Desc: "Reefs glitter under the waves. The water looks deceptively calm.", Exits: map[string]string{"east": "dock", "north": "mist_pier"}, Items: []string{"gale_fruit"}, Enemies: []string{"reef_beast"}, Tags: []string{"danger"}, CoordX: 0, CoordY: 1},
 		"jungle_path":   {ID: "jungle_path", Name: "Jungle Path", Island: "Ember Isle", Desc: "Vines twist like ropes. The ruins lie somewhere north.", Exits: map[string]string{"south": "market_lane", "north": "jungle_grove", "east": "ember_beach"}, Items: []string{"map_scrap"}, Tags: []string{}, CoordX: 1, CoordY: -1},
 		"jungle_grove":  {ID: "jungle_grove", Name: "Jungle Grove", Island: "Ember Isle", Desc: "A grove with glowing fungus and a gentle breeze.", Exits: map[string]string{"south": "jungle_path", "north": "ruins_gate", "east": "ember_village"}, Items: []string{"medkit", "balm"}, NPCs: []string{"herbalist"}, Tags: []string{}, CoordX: 1, CoordY: -2},
 		"ember_beach":   {ID: "ember_beach", Name: "Ember Beach", Island: "Ember Isle", Desc: "Black sand sparkles with heat.", Exits: map[string]string{"west": "jungle_path", "north": "ember_forge"}, Items: []string{"stone_fruit"}, Tags: []string{"danger"}, CoordX: 2, CoordY: -1},
-		"ember_village": {ID: "ember_village", Name: "Ember Village", Island: "Ember Isle", Desc: "A village of smokehouses and laughter.", Exits: map[string]string{"west": "jungle_grove", "east": "ember_forge"}, Items: []string{"pearl"}, NPCs: []string{"priest"}, Tags: []string{"shop"}, CoordX: 2, CoordY: -2},
-		"ember_forge":   {ID: "ember_forge", Name: "Ember Forge", Island: "Ember Isle", Desc: "A forge that never cools, guarded by a smuggler.", Exits: map[string]string{"south": "ember_beach", "west": "ember_village", "north": "ruins_gate"}, Items: []string{"glyph_frag_1", "cutlass"}, Enemies: []string{"smuggler"}, Tags: []string{"quest", "danger"}, CoordX: 2, CoordY: -3},
+		"ember_village": {ID: "ember_village", Name: "Ember Village", Island: "Ember Isle", Desc: "A village of smokehouses and laughter.", Exits: map[string]string{"west": "jungle_grove", "east": "ember_forge"}, Items: []string{}, NPCs: []string{"priest", "trader"}, Tags: []string{"shop", "lit"}, CoordX: 2, CoordY: -2},
+		"ember_forge":   {ID: "ember_forge", Name: "Ember Forge", Island: "Ember Isle", Desc: "A forge that never cools, guarded by a smuggler.", Exits: map[string]string{"south": "ember_beach", "west": "ember_village", "north": "ruins_gate"}, Items: []string{"glyph_frag_1", "cutlass", "forge_crates"}, Enemies: []string{"smuggler"}, Tags: []string{"quest", "danger", "forge", "lit"}, CoordX: 2, CoordY: -3},
 		"ruins_gate":    {ID: "ruins_gate", Name: "Ruins Gate", Island: "Ember Isle", Desc: "A stone gate carved with a riddle: 'Speak the sea and the stone will hear.'", Exits: map[string]string{"south": "jungle_grove", "north": "ruins_hall"}, Items: []string{"sun_coin"}, Tags: []string{"quest"}, CoordX: 1, CoordY: -3},
-		"ruins_hall":    {ID: "ruins_hall", Name: "Glyph Hall", Island: "Ember Isle", Desc: "Dusty pillars and faded carvings.", Exits: map[string]string{"south": "ruins_gate", "north": "ruins_core"}, Items: []string{"glyph_frag_2"}, Tags: []string{"quest"}, CoordX: 1, CoordY: -4},
-		"ruins_core":    {ID: "ruins_core", Name: "Glyph Core", Island: "Ember Isle", Desc: "A sealed chamber humming with the ocean's memory.", Exits: map[string]string{"south": "ruins_hall"}, Items: []string{}, Enemies: []string{"rival_pirate"}, Tags: []string{"quest", "danger"}, CoordX: 1, CoordY: -5},
+		"ruins_hall":    {ID: "ruins_hall", Name: "Glyph Hall", Island: "Ember Isle", Desc: "Dusty pillars and faded carvings.", Exits: map[string]string{"south": "ruins_gate", "north": "ruins_core"}, Items: []string{"glyph_frag_2"}, Tags: []string{"quest", "dark"}, CoordX: 1, CoordY: -4},
+		"ruins_core":    {ID: "ruins_core", Name: "Glyph Core", Island: "Ember Isle", Desc: "A sealed chamber humming with the ocean's memory.", Exits: map[string]string{"south": "ruins_hall"}, Items: []string{}, Enemies: []string{}, Tags: []string{"quest", "danger", "dark"}, CoordX: 1, CoordY: -5},
+		"sunken_spring": {ID: "sunken_spring", Name: "Sunken Spring", Island: "Ember Isle", Desc: "A spring pooled under giant roots. Smugglers' marks are scratched into the stones.", Exits: map[string]string{"east": "jungle_grove", "northeast": "ruins_gate"}, Items: []string{"bribe", "medkit"}, Tags: []string{"dark"}, CoordX: 0, CoordY: -2},
 		"mist_pier":     {ID: "mist_pier", Name: "Mist Pier", Island: "Mist Isle", Desc: "Fog rolls off the pier like breath.", Exits: map[string]string{"south": "reef_shallows", "north": "mist_library", "east": "mist_market"}, Items: []string{"spark_fruit"}, Tags: []string{"dock"}, CoordX: -1, CoordY: 1},
 		"mist_library":  {ID: "mist_library", Name: "Mist Library", Island: "Mist Isle", Desc: "Shelves of scrolls whisper in the fog.", Exits: map[string]string{"south": "mist_pier"}, Items: []string{"glyph_frag_3"}, NPCs: []string{"librarian"}, Tags: []string{"quest"}, CoordX: -1, CoordY: 0},
-		"mist_market":   {ID: "mist_market", Name: "Mist Market", Island: "Mist Isle", Desc: "Stalls glow with bioluminescent wares.", Exits: map[string]string{"west": "mist_pier"}, Items: []string{"smoke_bomb"}, Tags: []string{"shop"}, CoordX: 0, CoordY: 1},
+		"mist_market":   {ID: "mist_market", Name: "Mist Market", Island: "Mist Isle", Desc: "Stalls glow with bioluminescent wares.", Exits: map[string]string{"west": "mist_pier"}, Items: []string{}, NPCs: []string{"vendor"}, Tags: []string{"shop", "lit"}, CoordX: 0, CoordY: 1},
 		"sky_lift":      {ID: "sky_lift", Name: "Sky Lift", Island: "Skyline Atoll", Desc: "A lift platform rising toward the clouds.", Exits: map[string]string{"south": "mist_pier", "north": "sky_shrine"}, Items: []string{"chart"}, Tags: []string{"quest"}, CoordX: -2, CoordY: 0},
 		"sky_shrine":    {ID: "sky_shrine", Name: "Sky Shrine", Island: "Skyline Atoll", Desc: "A shrine in the clouds, lightning crackling nearby.", Exits: map[string]string{"south": "sky_lift"}, Items: []string{"stone_key"}, NPCs: []string{"priest"}, Tags: []string{"quest"}, CoordX: -2, CoordY: -1},
 	}
 
 	quests := map[string]*Quest{
-		"main":       {ID: "main", Name: "Glyph Stone Hunt", Desc: "Collect three Glyph Stone fragments and decipher their coordinates.", Active: true},
+		"main":       {ID: "main", Name: "Glyph Stone Hunt", Desc: "Collect three Glyph Stone fragments and decipher their coordinates.", Active: true, Faction: "scholars", RepReward: 2},
 		"dockhand":   {ID: "dockhand", Name: "Bandaged Dockhand", Desc: "Help the dockhand and earn their trust.", Active: true},
 		"gadgeteer":  {ID: "gadgeteer", Name: "Spice for Gadgets", Desc: "Trade spice for a cipher lens.", Active: true},
-		"broker":     {ID: "broker", Name: "Rum for Keys", Desc: "Trade rum for a stone key.", Active: true},
-		"priest":     {ID: "priest", Name: "Shrine Offering", Desc: "Bring a sun coin to the shrine keeper.", Active: true},
+		"broker":     {ID: "broker", Name: "Rum for Keys", Desc: "Trade rum for a stone key.", Active: true, Faction: "smugglers", RepReward: 2},
+		"priest":     {ID: "priest", Name: "Shrine Offering", Desc: "Bring a sun coin to the shrine keeper.", Active: true, Faction: "villagers", RepReward: 3},
 		"shipwright": {ID: "shipwright", Name: "Hull Repairs", Desc: "Deliver a repair kit for a dock pass.", Active: true},
-		"rival":      {ID: "rival", Name: "Rival Showdown", Desc: "Defeat the rival pirate in the ruins.", Active: true},
+		"officer":    {ID: "officer", Name: "Officer's Favour", Desc: "Deal with the spice smuggler at the Ember Forge, then report to the Bluecoat officer.", Active: true, Faction: "navy", RepReward: 2},
+		"rival":      {ID: "rival", Name: "Rival Showdown", Desc: "Stop the rival pirate before they reach the Glyph Core.", Active: true, Faction: "pirates", RepReward: -2},
 	}
 
 	islands := map[string]*Island{
 		"Harbor Isle":   {ID: "Harbor Isle", Name: "Harbor Isle", Desc: "A bustling island of trade and gossip.", Nodes: []string{"dock", "town_square", "tavern", "market_lane", "shipyard"}},
-		"Ember Isle":    {ID: "Ember Isle", Name: "Ember Isle", Desc: "A volcanic island with ancient ruins.", Nodes: []string{"jungle_path", "jungle_grove", "ember_beach", "ember_village", "ember_forge", "ruins_gate", "ruins_hall", "ruins_core"}},
+		"Ember Isle":    {ID: "Ember Isle", Name: "Ember Isle", Desc: "A volcanic island with ancient ruins.", Nodes: []string{"jungle_path", "jungle_grove", "sunken_spring", "ember_beach", "ember_village", "ember_forge", "ruins_gate", "ruins_hall", "ruins_core"}},
 		"Mist Isle":     {ID: "Mist Isle", Name: "Mist Isle", Desc: "An island cloaked in gentle fog.", Nodes: []string{"mist_pier", "mist_library", "mist_market"}},
 		"Skyline Atoll": {ID: "Skyline Atoll", Name: "Skyline Atoll", Desc: "A cloud-touched atoll of storms.", Nodes: []string{"sky_lift", "sky_shrine"}},
 		"Navy Bastion":  {ID: "Navy Bastion", Name: "Navy Bastion", Desc: "The Bluecoat Navy stronghold.", Nodes: []string{"navy_outpost"}},
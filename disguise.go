@@ -0,0 +1,68 @@
+package main
+
+import "fmt"
+
+type Disguise struct {
+	ID      string
+	Faction string
+	Cover   int
+}
+
+func Disguises() map[string]Disguise {
+	return map[string]Disguise{
+		"fisher_cloak": {ID: "fisher_cloak", Cover: 2},
+		"navy_uniform": {ID: "navy_uniform", Faction: "navy", Cover: 1},
+	}
+}
+
+func (g *GameState) wornDisguise() (Disguise, bool) {
+	g.checkEquipped()
+	disguise, ok := Disguises()[g.Player.Equipped["disguise"]]
+	return disguise, ok
+}
+
+func (g *GameState) DisguiseCover() int {
+	disguise, ok := g.wornDisguise()
+	if !ok {
+		return 0
+	}
+	return disguise.Cover
+}
+
+func (g *GameState) Wear(name string) string {
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	item := g.Items[itemID]
+	if _, ok := Disguises()[itemID]; !ok {
+		return g.refuse("That won't fool anyone.")
+	}
+	g.Player.Equipped["disguise"] = itemID
+	return fmt.Sprintf("You slip into the %s. Nobody looks twice.", item.Name)
+}
+
+func (g *GameState) Unwear() string {
+	g.checkEquipped()
+	itemID := g.Player.Equipped["disguise"]
+	if itemID == "" {
+		return g.refuse("You aren't wearing a disguise.")
+	}
+	g.Player.Equipped["disguise"] = ""
+	return fmt.Sprintf("You take off the %s.", g.Items[itemID].Name)
+}
+
+func (g *GameState) InNavyUniform() bool {
+	disguise, ok := g.wornDisguise()
+	return ok && disguise.Faction == "navy"
+}
+
+func (g *GameState) BluecoatSuspicion() string {
+	if g.Heat[g.HeatIsland()] >= 4 {
+		return "The sentry squints. 'I know that face from the posters!' Your uniform fools no one."
+	}
+	if found := g.contrabandIn(g.Player.Inventory); len(found) > 0 {
+		return "The sentry eyes the " + g.ListItemNames(found) + " you're carrying. 'That's not regulation kit.'"
+	}
+	return ""
+}
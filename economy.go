@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+func TradeGoods() map[string]map[string]float64 {
+	return map[string]map[string]float64{
+		"spice": {"Harbor Isle": 0.9, "Ember Isle": 0.7, "Mist Isle": 1.6, "Skyline Atoll": 1.4},
+		"pearl": {"Harbor Isle": 1.5, "Ember Isle": 0.7, "Mist Isle": 1.2, "Navy Bastion": 1.4},
+		"rum":   {"Harbor Isle": 0.9, "Mist Isle": 1.3, "Skyline Atoll": 1.5},
+	}
+}
+
+func (g *GameState) restockLevel(itemID string) int {
+	switch g.Items[itemID].Type {
+	case "consumable", "trade":
+		return 3
+	default:
+		return 1
+	}
+}
+
+func (g *GameState) InitEconomy() {
+	g.Stock = map[string]map[string]int{}
+	for id, npc := range g.NPCs {
+		if len(npc.Shop) == 0 {
+			continue
+		}
+		g.Stock[id] = map[string]int{}
+	}
+	g.RestockShops()
+	g.Market = map[string]map[string]float64{}
+	for itemID, bases := range TradeGoods() {
+		for island, base := range bases {
+			if g.Market[island] == nil {
+				g.Market[island] = map[string]float64{}
+			}
+			g.Market[island][itemID] = base
+		}
+	}
+	g.Haggled = map[string]float64{}
+}
+
+func (g *GameState) RestockShops() {
+	for npcID, stock := range g.Stock {
+		for _, itemID := range g.NPCs[npcID].Shop {
+			stock[itemID] = max(stock[itemID], g.restockLevel(itemID))
+		}
+	}
+}
+
+func (g *GameState) DriftPrices() {
+	goods := TradeGoods()
+	for island, prices := range g.Market {
+		for itemID, current := range prices {
+			base, ok := goods[itemID][island]
+			if !ok {
+				base = 1
+			}
+			drifted := current + (base-current)*0.25 + (rand.Float64()-0.5)*0.1
+			prices[itemID] = math.Max(0.5, math.Min(2.0, drifted))
+		}
+	}
+}
+
+func (g *GameState) StartTradingDay() {
+	g.RestockShops()
+	g.DriftPrices()
+	g.Haggled = map[string]float64{}
+}
+
+func (g *GameState) marketRate(itemID string) float64 {
+	island := g.Room().Island
+	if rate, ok := g.Market[island][itemID]; ok {
+		return rate
+	}
+	return 1
+}
+
+func (g *GameState) nudgeMarket(itemID string, delta float64) {
+	island := g.Room().Island
+	if g.Market[island] == nil {
+		g.Market[island] = map[string]float64{}
+	}
+	rate := g.marketRate(itemID) + delta
+	g.Market[island][itemID] = math.Max(0.5, math.Min(2.0, rate))
+}
+
+func (g *GameState) BuyPrice(itemID, npcID string) int {
+	item := g.Items[itemID]
+	base := float64(item.Value) * g.marketRate(itemID) * (1 - g.Haggled[npcID])
+	if item.Contraband && g.NPCs[npcID].Fence {
+		base *= 1.25
+	}
+	return max(1, g.Price(int(math.Round(base)), g.NPCs[npcID].Faction))
+}
+
+func (g *GameState) SellPrice(itemID, npcID string) int {
+	item := g.Items[itemID]
+	rate := 0.5
+	if g.NPCs[npcID].Fence {
+		rate = 0.4
+		if item.Contraband {
+			rate = 0.8
+		}
+	}
+	base := float64(item.Value) * rate * g.marketRate(itemID) * (1 + g.Haggled[npcID]) * (1 + g.repDiscount(g.NPCs[npcID].Faction))
+	return int(math.Round(base))
+}
+
+func (g *GameState) fenceHere() string {
+	for _, npcID := range g.Merchants() {
+		if g.NPCs[npcID].Fence {
+			return npcID
+		}
+	}
+	return ""
+}
+
+func (g *GameState) Merchants() []string {
+	merchants := []string{}
+	for _, npcID := range g.Room().NPCs {
+		if _, ok := g.Stock[npcID]; ok && g.Mood(npcID) != "hostile" {
+			merchants = append(merchants, npcID)
+		}
+	}
+	return merchants
+}
+
+func (g *GameState) stockIDs(npcID string) []string {
+	ids := []string{}
+	for itemID, qty := range g.Stock[npcID] {
+		if qty > 0 {
+			ids = append(ids, itemID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (g *GameState) pickMerchant(name string) (string, string) {
+	merchants := g.Merchants()
+	if len(merchants) == 0 {
+		return "", "There's no one trading here."
+	}
+	if name == "" {
+		return merchants[0], ""
+	}
+	if npcID := g.FindNPC(name, merchants); npcID != "" {
+		return npcID, ""
+	}
+	return "", "They aren't trading."
+}
+
+func (g *GameState) Wares() string {
+	merchants := g.Merchants()
+	if len(merchants) == 0 {
+		return "There's nothing for sale here."
+	}
+	lines := []string{}
+	for _, npcID := range merchants {
+		lines = append(lines, g.NPCs[npcID].Name+" sells:")
+		ids := g.stockIDs(npcID)
+		if len(ids) == 0 {
+			lines = append(lines, "- Sold out until tomorrow.")
+		}
+		for _, itemID := range ids {
+			lines = append(lines, fmt.Sprintf("- %s x%d, %d coins", g.Items[itemID].Name, g.Stock[npcID][itemID], g.BuyPrice(itemID, npcID)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) Buy(itemName string) string {
+	merchants := g.Merchants()
+	if len(merchants) == 0 {
+		return g.refuse("There's nothing for sale here.")
+	}
+	for _, npcID := range merchants {
+		itemID := g.FindItem(itemName, g.stockIDs(npcID))
+		if itemID == "" {
+			continue
+		}
+		item := g.Items[itemID]
+		price := g.BuyPrice(itemID, npcID)
+		if g.Money < price {
+			return g.refuse("You can't afford that.")
+		}
+		if g.InventorySlots()+g.slotsFor(itemID) > g.Player.MaxSlots {
+			return g.refuse("You're carrying too much already.")
+		}
+		g.Money -= price
+		g.Stock[npcID][itemID]--
+		g.Player.Inventory = append(g.Player.Inventory, itemID)
+		g.nudgeMarket(itemID, 0.1)
+		g.spendHaggle(npcID)
+		if price >= 30 {
+			g.AdjustRep(g.NPCs[npcID].Faction, 1)
+		}
+		return fmt.Sprintf("You buy %s from the %s for %d coins.", item.Name, g.NPCs[npcID].Name, price)
+	}
+	return g.refuse("That item isn't for sale here.")
+}
+
+func (g *GameState) BuyCount(itemName string, count int) string {
+	if count <= 1 {
+		return g.Buy(itemName)
+	}
+	spent, bought, result := g.Money, 0, ""
+	var itemID string
+	for bought < count {
+		held := len(g.Player.Inventory)
+		result = g.Buy(itemName)
+		if len(g.Player.Inventory) == held {
+			break
+		}
+		itemID = g.Player.Inventory[len(g.Player.Inventory)-1]
+		bought++
+	}
+	if bought <= 1 {
+		return result
+	}
+	message := fmt.Sprintf("You buy %s for %d coins.", g.stackName(itemID, bought), spent-g.Money)
+	if bought < count {
+		message += " " + result
+	}
+	return message
+}
+
+func (g *GameState) Sell(itemName string, merchant string) string {
+	return g.SellCount(itemName, merchant, 1)
+}
+
+func (g *GameState) SellAll(merchant string, except []string) string {
+	lines := []string{}
+	for _, itemID := range stackIDs(g.Player.Inventory) {
+		item := g.Items[itemID]
+		if (item.Type != "trade" && !item.Contraband) || g.excluded(itemID, except) {
+			continue
+		}
+		lines = append(lines, g.SellCount(itemID, merchant, countID(g.Player.Inventory, itemID)))
+	}
+	if len(lines) == 0 {
+		return g.refuse("You have no cargo to sell.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) SellCount(itemName string, merchant string, count int) string {
+	if len(g.Merchants()) == 0 {
+		return g.refuse("No one is buying here.")
+	}
+	itemID := g.FindItem(itemName, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that to sell.")
+	}
+	if refusal := g.notEmpty(itemID); refusal != "" {
+		return g.refuse(refusal)
+	}
+	item := g.Items[itemID]
+	if merchant == "" && item.Contraband {
+		merchant = g.fenceHere()
+	}
+	npcID, refusal := g.pickMerchant(merchant)
+	if npcID == "" {
+		return g.refuse(refusal)
+	}
+	if item.Contraband && !g.NPCs[npcID].Fence {
+		return g.refuse(g.honestRefusal(npcID, itemID))
+	}
+	count = min(count, countID(g.Player.Inventory, itemID))
+	total := 0
+	for i := 0; i < count; i++ {
+		sale := g.SellPrice(itemID, npcID)
+		total += sale
+		g.Money += sale
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+		g.Stock[npcID][itemID]++
+		g.nudgeMarket(itemID, -0.1)
+		if sale >= 30 {
+			g.AdjustRep(g.NPCs[npcID].Faction, 1)
+		}
+	}
+	g.spendHaggle(npcID)
+	if count == 1 {
+		return fmt.Sprintf("You sell %s to the %s for %d coins.", item.Name, g.NPCs[npcID].Name, total)
+	}
+	return fmt.Sprintf("You sell %s to the %s for %d coins.", g.stackName(itemID, count), g.NPCs[npcID].Name, total)
+}
+
+func (g *GameState) spendHaggle(npcID string) {
+	if _, ok := g.Haggled[npcID]; ok {
+		g.Haggled[npcID] = 0
+	}
+}
+
+func (g *GameState) Haggle(name string) string {
+	npcID, refusal := g.pickMerchant(name)
+	if npcID == "" {
+		return g.refuse(refusal)
+	}
+	npc := g.NPCs[npcID]
+	if _, tried := g.Haggled[npcID]; tried {
+		return g.refuse(fmt.Sprintf("The %s has heard enough of your bargaining for one day.", npc.Name))
+	}
+	if g.SkillCheck("charm") {
+		g.Haggled[npcID] = 0.2
+		return fmt.Sprintf("The %s sighs and agrees to a better deal on your next trade.", npc.Name)
+	}
+	g.Haggled[npcID] = -0.1
+	return fmt.Sprintf("The %s bristles. Prices just went up for you.", npc.Name)
+}
@@ -0,0 +1,126 @@
+package main
+
+type EventKind int
+
+const (
+	ItemTaken EventKind = iota
+	RoomEntered
+	EnemyDefeated
+	FlagSet
+	QuestCompleted
+	DayStarted
+	AchievementUnlocked
+	ItemCrafted
+	SecretFound
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case ItemTaken:
+		return "item_taken"
+	case RoomEntered:
+		return "room_entered"
+	case EnemyDefeated:
+		return "enemy_defeated"
+	case FlagSet:
+		return "flag_set"
+	case QuestCompleted:
+		return "quest_completed"
+	case DayStarted:
+		return "day_started"
+	case AchievementUnlocked:
+		return "achievement_unlocked"
+	case ItemCrafted:
+		return "item_crafted"
+	case SecretFound:
+		return "secret_found"
+	default:
+		return "unknown"
+	}
+}
+
+type Event struct {
+	Kind   EventKind
+	ID     string
+	Detail string
+}
+
+type EventHandler func(g *GameState, event Event)
+
+type EventBus struct {
+	handlers map[EventKind][]EventHandler
+	any      []EventHandler
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: map[EventKind][]EventHandler{}}
+}
+
+func (b *EventBus) Subscribe(kind EventKind, handler EventHandler) {
+	b.handlers[kind] = append(b.handlers[kind], handler)
+}
+
+func (b *EventBus) SubscribeAll(handler EventHandler) {
+	b.any = append(b.any, handler)
+}
+
+func (g *GameState) Emit(kind EventKind, id string, detail string) {
+	if g.Events == nil {
+		return
+	}
+	event := Event{Kind: kind, ID: id, Detail: detail}
+	for _, handler := range g.Events.any {
+		handler(g, event)
+	}
+	for _, handler := range g.Events.handlers[kind] {
+		handler(g, event)
+	}
+}
+
+func (g *GameState) SetFlag(name string) {
+	if g.Flags[name] {
+		return
+	}
+	g.Flags[name] = true
+	g.Emit(FlagSet, name, "")
+}
+
+func (g *GameState) subscribeCore() {
+	bus := g.Events
+	bus.SubscribeAll(func(g *GameState, event Event) {
+		g.Stats[event.Kind.String()]++
+	})
+	bus.Subscribe(ItemTaken, func(g *GameState, event Event) {
+		if item := g.Items[event.ID]; item != nil && item.Contraband {
+			g.AddWanted(1)
+		}
+	})
+	bus.Subscribe(RoomEntered, func(g *GameState, event Event) {
+		g.MaybePatrol()
+	})
+	bus.Subscribe(EnemyDefeated, func(g *GameState, event Event) {
+		switch event.ID {
+		case "rival_pirate":
+			g.DefeatRival()
+			g.CompleteQuest("rival", "You beat your rival to the treasure.")
+		case "smuggler":
+			g.CompleteQuest("officer", "The forge smuggler is dealt with. Report to the officer.")
+		}
+	})
+	for _, kind := range []EventKind{ItemTaken, ItemCrafted, FlagSet, EnemyDefeated} {
+		bus.Subscribe(kind, func(g *GameState, event Event) {
+			if g.Combat == nil {
+				g.ResolveQuests()
+			}
+		})
+	}
+	bus.Subscribe(DayStarted, func(g *GameState, event Event) {
+		g.PayCrew()
+		g.StartTradingDay()
+		g.CoolHeat()
+		g.RunDayHooks()
+	})
+	bus.SubscribeAll(func(g *GameState, event Event) {
+		g.CheckAchievements(event)
+	})
+}
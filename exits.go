@@ -0,0 +1,110 @@
+package main
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed assets/rooms.json
+var builtinRoomRules []byte
+
+type ExitRule struct {
+	ID      string
+	Room    string
+	Exit    string
+	To      string
+	When    []RuleCondition
+	Check   string
+	Effects []RuleEffect
+	Message string
+}
+
+type RoomVariant struct {
+	Room string
+	When []RuleCondition
+	Desc string
+	Note string
+}
+
+type RoomRules struct {
+	Exits    []ExitRule
+	Variants []RoomVariant
+}
+
+func LoadRoomRules() (RoomRules, error) {
+	var rules RoomRules
+	err := loadContent("rooms.json", builtinRoomRules, &rules)
+	return rules, err
+}
+
+func (g *GameState) IsNight() bool {
+	return g.TimeOfDay >= 20 || g.TimeOfDay < 6
+}
+
+func (g *GameState) exitRules(roomID string, direction string) []ExitRule {
+	dest := g.Rooms[roomID].Exits[direction]
+	matched := []ExitRule{}
+	for _, rule := range g.RoomRules.Exits {
+		if (rule.Room != "" && rule.Room != roomID) || (rule.Exit != "" && rule.Exit != direction) || (rule.To != "" && rule.To != dest) {
+			continue
+		}
+		if g.conditionsHold(rule.When, "") {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+func (g *GameState) ExitBarred(roomID string, direction string) bool {
+	return len(g.exitRules(roomID, direction)) > 0
+}
+
+func (g *GameState) ExitRefusal(direction string) string {
+	for _, rule := range g.exitRules(g.Player.Location, direction) {
+		if rule.Check == "bluecoat" && g.InNavyUniform() {
+			message := g.BluecoatSuspicion()
+			if message == "" && g.SkillCheck("wits") {
+				g.AddLog("You snap a crisp salute. The sentry waves you through.", "event")
+				continue
+			}
+			if message == "" {
+				message = "You fumble the salute. The sentry raises the alarm."
+			}
+			g.AddWanted(1)
+			return message
+		}
+		for _, effect := range rule.Effects {
+			g.applyEffect(effect)
+		}
+		return rule.Message
+	}
+	return ""
+}
+
+func (g *GameState) RoomDesc(room *Room) string {
+	desc, notes := room.Desc, []string{}
+	replaced := false
+	for _, variant := range g.RoomRules.Variants {
+		if (variant.Room != "" && variant.Room != room.ID) || !g.conditionsHold(variant.When, "") {
+			continue
+		}
+		if variant.Desc != "" && !replaced {
+			desc, replaced = variant.Desc, true
+		}
+		if variant.Note != "" {
+			notes = append(notes, variant.Note)
+		}
+	}
+	return strings.Join(append([]string{desc}, notes...), " ")
+}
+
+func (g *GameState) exitLabels(room *Room) []string {
+	labels := []string{}
+	for _, direction := range exitKeys(room.Exits) {
+		if g.ExitBarred(room.ID, direction) {
+			direction += " (barred)"
+		}
+		labels = append(labels, direction)
+	}
+	return labels
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+type Faction struct {
+	ID      string
+	Name    string
+	Rivals  []string
+	Ambush  string
+	Ambient string
+}
+
+func Factions() map[string]Faction {
+	return map[string]Faction{
+		"navy":      {ID: "navy", Name: "Bluecoat Navy", Rivals: []string{"smugglers", "pirates"}},
+		"smugglers": {ID: "smugglers", Name: "Smugglers", Rivals: []string{"navy"}, Ambush: "smuggler", Ambient: "A smuggler crew slips out of hiding, knives drawn."},
+		"villagers": {ID: "villagers", Name: "Ember Villagers"},
+		"scholars":  {ID: "scholars", Name: "Mist Scholars"},
+		"pirates":   {ID: "pirates", Name: "Rival Pirates", Rivals: []string{"navy"}, Ambush: "raider", Ambient: "Rival pirates drop from the rigging of a nearby wreck!"},
+	}
+}
+
+func factionIDs() []string {
+	ids := []string{}
+	for id := range Factions() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (g *GameState) AdjustRep(faction string, delta int) {
+	if faction == "" || delta == 0 {
+		return
+	}
+	g.SetReputation(faction, g.Reputation[faction]+delta)
+}
+
+func (g *GameState) SetReputation(faction string, value int) {
+	before := g.Reputation[faction]
+	g.Reputation[faction] = value
+	for id, npc := range g.NPCs {
+		if npc.Faction != faction {
+			continue
+		}
+		if value <= -3 && before > -3 {
+			g.SetMood(id, "hostile")
+		}
+		if value >= 3 && before < 3 && g.Mood(id) != "hostile" {
+			g.SetMood(id, "friendly")
+		}
+		if value > -3 && before <= -3 && g.Mood(id) == "hostile" {
+			g.SetMood(id, npc.Disposition)
+		}
+	}
+}
+
+func (g *GameState) FactionCrime(faction string, severity int) {
+	if faction == "" {
+		return
+	}
+	if faction == "navy" {
+		g.AddWanted(severity)
+		return
+	}
+	g.AdjustRep(faction, -severity-1)
+	for _, rival := range Factions()[faction].Rivals {
+		if rival == "navy" {
+			g.AddWanted(-1)
+		} else {
+			g.AdjustRep(rival, 1)
+		}
+	}
+}
+
+func (g *GameState) repDiscount(faction string) float64 {
+	if faction == "" {
+		return 0
+	}
+	discount := float64(g.Reputation[faction]) * 0.04
+	if discount > 0.25 {
+		discount = 0.25
+	}
+	if discount < -0.25 {
+		discount = -0.25
+	}
+	return discount
+}
+
+func repLabel(value int) string {
+	switch {
+	case value <= -5:
+		return "hated"
+	case value <= -2:
+		return "distrusted"
+	case value < 2:
+		return "neutral"
+	case value < 5:
+		return "liked"
+	default:
+		return "honoured"
+	}
+}
+
+func (g *GameState) ReputationReport() string {
+	factions := Factions()
+	lines := []string{"Standing:"}
+	for _, id := range factionIDs() {
+		value := g.Reputation[id]
+		lines = append(lines, fmt.Sprintf("- %s: %d (%s)", factions[id].Name, value, repLabel(value)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) MaybeAmbush() {
+	room := g.Room()
+	if room == nil || len(room.Enemies) > 0 || !contains(room.Tags, "danger") {
+		return
+	}
+	for _, id := range factionIDs() {
+		faction := Factions()[id]
+		if faction.Ambush == "" || g.Reputation[id] > -3 {
+			continue
+		}
+		chance := 0.25
+		if g.InDarkness() {
+			chance = 0.45
+		}
+		if rand.Float64() < chance {
+			room.Enemies = append(room.Enemies, faction.Ambush)
+			g.AddLog(faction.Ambient, "event")
+			return
+		}
+	}
+}
+
+func (g *GameState) CompleteQuest(id string, outcome string) {
+	quest, ok := g.Quests[id]
+	if !ok || quest.Done {
+		return
+	}
+	quest.Done = true
+	quest.Outcome = outcome
+	g.AdjustRep(quest.Faction, quest.RepReward)
+	g.Emit(QuestCompleted, id, outcome)
+}
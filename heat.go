@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (g *GameState) HeatIsland() string {
+	room := g.Room()
+	if room == nil || room.Island == "Ship" {
+		return "Harbor Isle"
+	}
+	return room.Island
+}
+
+func (g *GameState) Wanted() int {
+	return max(0, g.Heat[g.HeatIsland()]-g.DisguiseCover())
+}
+
+func (g *GameState) AddWanted(amount int) {
+	island := g.HeatIsland()
+	g.Heat[island] = max(0, g.Heat[island]+amount)
+	if amount > 0 {
+		g.WitnessCrime()
+		g.LastCrime[island] = g.Day
+		g.AdjustRep("navy", -amount)
+	}
+}
+
+func (g *GameState) CoolHeat() {
+	for island, heat := range g.Heat {
+		if heat > 0 && g.Day-g.LastCrime[island] >= 2 {
+			g.Heat[island] = heat - 1
+			if g.Heat[island] == 0 {
+				g.AddLog("The posters on "+island+" are peeling. They've forgotten your face.", "event")
+			}
+		}
+	}
+}
+
+func (g *GameState) officerHere() string {
+	for _, npcID := range g.Room().NPCs {
+		if g.NPCs[npcID].Faction == "navy" {
+			return npcID
+		}
+	}
+	return ""
+}
+
+func (g *GameState) PayBounty() string {
+	if !g.HasItem("bounty_poster") {
+		return g.refuse("You need a bounty poster to know what you owe.")
+	}
+	officerID := g.officerHere()
+	if officerID == "" {
+		return g.refuse("There's no Bluecoat here to take your payment.")
+	}
+	island := g.HeatIsland()
+	heat := g.Heat[island]
+	if heat == 0 {
+		return g.refuse("Your name isn't on any poster here.")
+	}
+	cost := g.Price(heat*15, "navy")
+	if g.Money < cost {
+		return g.refuse(fmt.Sprintf("The bounty on %s is %d coins. You can't cover it.", island, cost))
+	}
+	g.Money -= cost
+	g.Heat[island] = 0
+	g.AdjustRep("navy", heat)
+	g.removeCarried("bounty_poster")
+	return fmt.Sprintf("The %s stamps the poster PAID. %d coins clear your name on %s.", g.NPCs[officerID].Name, cost, island)
+}
+
+func (g *GameState) HeatReport() string {
+	islands := []string{}
+	for island, heat := range g.Heat {
+		if heat > 0 {
+			islands = append(islands, island)
+		}
+	}
+	if len(islands) == 0 {
+		return "Heat: none"
+	}
+	sort.Strings(islands)
+	parts := []string{}
+	for _, island := range islands {
+		parts = append(parts, fmt.Sprintf("%s %d", island, g.Heat[island]))
+	}
+	return "Heat: " + strings.Join(parts, ", ")
+}
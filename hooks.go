@@ -0,0 +1,169 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed assets/scripts
+var builtinScripts embed.FS
+
+func LoadScripts() (map[string]*Script, []string) {
+	var files fs.FS = os.DirFS(path.Join("assets", "scripts"))
+	if _, err := fs.Stat(files, "."); err != nil {
+		files, _ = fs.Sub(builtinScripts, "assets/scripts")
+	}
+	scripts := map[string]*Script{}
+	problems := []string{}
+	fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(name, ".lua") {
+			return nil
+		}
+		source, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil
+		}
+		key := strings.TrimSuffix(name, ".lua")
+		script, err := ParseScript(key, string(source))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Script %s: %v", key, err))
+			return nil
+		}
+		scripts[key] = script
+		return nil
+	})
+	return scripts, problems
+}
+
+func scriptArg(args []interface{}, i int) string {
+	if i >= len(args) || args[i] == nil {
+		return ""
+	}
+	return fmt.Sprint(args[i])
+}
+
+func (g *GameState) scriptAPI() map[string]scriptFunc {
+	return map[string]scriptFunc{
+		"log": func(args []interface{}) (interface{}, error) {
+			g.AddLog(scriptArg(args, 0), "event")
+			return nil, nil
+		},
+		"flag": func(args []interface{}) (interface{}, error) {
+			return g.Flags[scriptArg(args, 0)], nil
+		},
+		"set": func(args []interface{}) (interface{}, error) {
+			g.SetFlag(scriptArg(args, 0))
+			return nil, nil
+		},
+		"unset": func(args []interface{}) (interface{}, error) {
+			delete(g.Flags, scriptArg(args, 0))
+			return nil, nil
+		},
+		"has": func(args []interface{}) (interface{}, error) {
+			return g.HasItem(scriptArg(args, 0)), nil
+		},
+		"give": func(args []interface{}) (interface{}, error) {
+			itemID := scriptArg(args, 0)
+			if _, ok := g.Items[itemID]; !ok {
+				return nil, fmt.Errorf("no item %q", itemID)
+			}
+			g.Player.Inventory = append(g.Player.Inventory, itemID)
+			return nil, nil
+		},
+		"take": func(args []interface{}) (interface{}, error) {
+			itemID := scriptArg(args, 0)
+			had := g.HasItem(itemID)
+			g.removeCarried(itemID)
+			return had, nil
+		},
+		"move": func(args []interface{}) (interface{}, error) {
+			roomID := scriptArg(args, 0)
+			if _, ok := g.Rooms[roomID]; !ok {
+				return nil, fmt.Errorf("no room %q", roomID)
+			}
+			g.Player.Location = roomID
+			g.MarkDiscovered(roomID)
+			return nil, nil
+		},
+		"spawn": func(args []interface{}) (interface{}, error) {
+			enemyID := scriptArg(args, 0)
+			if _, ok := g.Enemies[enemyID]; !ok {
+				return nil, fmt.Errorf("no enemy %q", enemyID)
+			}
+			room := g.Room()
+			if roomID := scriptArg(args, 1); roomID != "" {
+				room = g.Rooms[roomID]
+			}
+			if room == nil {
+				return nil, fmt.Errorf("no room %q", scriptArg(args, 1))
+			}
+			room.Enemies = append(room.Enemies, enemyID)
+			return nil, nil
+		},
+		"open_exit": func(args []interface{}) (interface{}, error) {
+			roomID, dest := scriptArg(args, 0), scriptArg(args, 2)
+			if _, ok := g.Rooms[roomID]; !ok {
+				return nil, fmt.Errorf("no room %q", roomID)
+			}
+			if _, ok := g.Rooms[dest]; !ok {
+				return nil, fmt.Errorf("no room %q", dest)
+			}
+			g.OpenExit(roomID, scriptArg(args, 1), dest)
+			return nil, nil
+		},
+		"close_exit": func(args []interface{}) (interface{}, error) {
+			g.CloseExit(scriptArg(args, 0), scriptArg(args, 1))
+			return nil, nil
+		},
+		"location": func(args []interface{}) (interface{}, error) {
+			return g.Player.Location, nil
+		},
+		"day": func(args []interface{}) (interface{}, error) {
+			return g.Day, nil
+		},
+		"hour": func(args []interface{}) (interface{}, error) {
+			return g.TimeOfDay, nil
+		},
+		"chance": func(args []interface{}) (interface{}, error) {
+			percent, _ := strconv.Atoi(scriptArg(args, 0))
+			return rand.Intn(100) < percent, nil
+		},
+	}
+}
+
+func (g *GameState) RunHook(scriptName string, hook string, context map[string]interface{}) string {
+	script, ok := g.Scripts[scriptName]
+	if !ok {
+		return ""
+	}
+	vm := &scriptVM{Globals: context, API: g.scriptAPI()}
+	value, err := vm.Call(script, hook)
+	if err != nil {
+		g.AddLog(fmt.Sprintf("Script %s.%s failed: %v", scriptName, hook, err), "system")
+		return ""
+	}
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func (g *GameState) RunDayHooks() {
+	names := []string{}
+	for name, script := range g.Scripts {
+		if _, ok := script.Functions["on_day_start"]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.RunHook(name, "on_day_start", map[string]interface{}{"day": g.Day})
+	}
+}
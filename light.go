@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Light struct {
+	ID        string
+	Hours     int
+	BurnsOut  bool
+	OneShot   bool
+	Refill    string
+	BurnedOut string
+}
+
+func Lights() map[string]Light {
+	return map[string]Light{
+		"storm_lantern": {ID: "storm_lantern", Hours: 10, Refill: "lamp_oil", BurnedOut: "Your lantern gutters and dies. It needs oil."},
+		"torch":         {ID: "torch", Hours: 3, BurnsOut: true, BurnedOut: "Your torch burns down to a stub. You toss it."},
+		"flare":         {ID: "flare", Hours: 1, BurnsOut: true, OneShot: true, BurnedOut: "The flare fizzles out in a cloud of red smoke."},
+	}
+}
+
+func (g *GameState) RoomIsDark(room *Room) bool {
+	return contains(room.Tags, "dark") || (g.IsNight() && !contains(room.Tags, "lit"))
+}
+
+func (g *GameState) InDarkness() bool {
+	return g.Player.Lit == "" && g.RoomIsDark(g.Room())
+}
+
+func (g *GameState) visibleItems() []string {
+	if g.InDarkness() {
+		return nil
+	}
+	return g.Room().Items
+}
+
+func (g *GameState) checkLight() bool {
+	if g.Player.Lit != "" && !contains(g.Player.Inventory, g.Player.Lit) {
+		g.Player.Lit = ""
+	}
+	return g.Player.Lit != ""
+}
+
+func (g *GameState) fuel(itemID string) int {
+	if fuel, ok := g.Player.Fuel[itemID]; ok {
+		return fuel
+	}
+	return Lights()[itemID].Hours
+}
+
+func (g *GameState) LightUp(name string) string {
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	item := g.Items[itemID]
+	if _, ok := Lights()[itemID]; !ok {
+		return g.refuse(fmt.Sprintf("The %s won't give you much light.", item.Name))
+	}
+	if g.Player.Lit == itemID {
+		return fmt.Sprintf("The %s is already lit.", item.Name)
+	}
+	if g.fuel(itemID) <= 0 {
+		return g.refuse(fmt.Sprintf("The %s is dry. It needs %s.", item.Name, g.Items[Lights()[itemID].Refill].Name))
+	}
+	lines := []string{}
+	if g.Player.Lit != "" {
+		lines = append(lines, g.Douse(g.Player.Lit))
+	}
+	g.Player.Lit = itemID
+	g.Player.Fuel[itemID] = g.fuel(itemID)
+	lines = append(lines, fmt.Sprintf("You light the %s. (%dh of light)", item.Name, g.Player.Fuel[itemID]))
+	if message := g.UsePuzzleItem(itemID); message != "" {
+		lines = append(lines, message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) Douse(name string) string {
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" || itemID != g.Player.Lit {
+		return g.refuse("You've nothing like that burning.")
+	}
+	g.Player.Lit = ""
+	if Lights()[itemID].OneShot {
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+		delete(g.Player.Fuel, itemID)
+		return fmt.Sprintf("You smother the %s. It's spent.", g.Items[itemID].Name)
+	}
+	return fmt.Sprintf("You put out the %s.", g.Items[itemID].Name)
+}
+
+func (g *GameState) Refuel(oilID string) string {
+	for lightID, light := range Lights() {
+		if light.Refill != oilID || !g.HasItem(lightID) {
+			continue
+		}
+		g.Player.Inventory = removeOne(g.Player.Inventory, oilID)
+		g.Player.Fuel[lightID] = light.Hours
+		return fmt.Sprintf("You fill the %s. (%dh of light)", g.Items[lightID].Name, light.Hours)
+	}
+	return g.refuse("You've nothing to fill with it.")
+}
+
+func (g *GameState) TickLight() {
+	itemID := g.Player.Lit
+	if !g.checkLight() {
+		return
+	}
+	g.Player.Fuel[itemID]--
+	if g.Player.Fuel[itemID] > 0 {
+		return
+	}
+	light := Lights()[itemID]
+	g.Player.Lit = ""
+	if light.BurnsOut {
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+		delete(g.Player.Fuel, itemID)
+	}
+	g.AddLog(light.BurnedOut, "event")
+}
+
+func (g *GameState) LightReport() string {
+	if g.Player.Lit == "" {
+		return ""
+	}
+	return fmt.Sprintf("Light: %s (%dh left)", g.Items[g.Player.Lit].Name, g.Player.Fuel[g.Player.Lit])
+}
+
+func (g *GameState) DarkLook(room *Room) string {
+	lines := []string{fmt.Sprintf("%s - %s", room.Name, room.Island), "It's too dark to see much. A lantern or torch would help."}
+	if len(room.NPCs) > 0 {
+		lines = append(lines, "You hear someone breathing nearby.")
+	}
+	if len(room.Enemies) > 0 {
+		lines = append(lines, "Something shifts in the darkness.")
+	}
+	lines = append(lines, "Exits: "+strings.Join(g.exitLabels(room), ", "))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func darkGame() (*GameState, *CommandProcessor) {
+	g := NewGameState()
+	g.Player.Location = "sunken_spring"
+	g.Player.Inventory = []string{"storm_lantern", "satchel"}
+	g.Player.Wits = -40
+	g.Room().Items = []string{"medkit", "bribe"}
+	g.container("satchel").Items = []string{"pearl"}
+	return g, NewCommandProcessor()
+}
+
+func TestDarkness(t *testing.T) {
+	g, _ := darkGame()
+	if !g.InDarkness() {
+		t.Fatalf("a dark-tagged room isn't dark")
+	}
+	g.Player.Location = "jungle_grove"
+	g.TimeOfDay = 23
+	if !g.InDarkness() {
+		t.Errorf("an unlit room isn't dark at night")
+	}
+	g.Player.Location = "market_lane"
+	if g.InDarkness() {
+		t.Errorf("a lit room is dark at night")
+	}
+}
+
+func TestDarknessHidesRoomItems(t *testing.T) {
+	g, c := darkGame()
+	if got := c.Complete(g, "take "); len(got) != 0 {
+		t.Errorf("Complete(take) in the dark = %q", got)
+	}
+	if got := g.MatchNames("med", g.scopeIDs("near")); len(got) != 0 {
+		t.Errorf("near scope in the dark matched %v", got)
+	}
+	if got := c.Execute(g, "take med"); got[0] != "You don't see that here." {
+		t.Errorf("take med in the dark = %q", got)
+	}
+	if got := c.Execute(g, "look in satchel"); got[0] != "It's too dark to see inside." {
+		t.Errorf("look in satchel in the dark = %q", got)
+	}
+	if got := c.Execute(g, "take pearl from satchel"); got[0] != "You rummage in the Leather Satchel but can't tell one thing from another in the dark." {
+		t.Errorf("take from satchel in the dark = %q", got)
+	}
+	c.Execute(g, "light lantern")
+	if got := c.Complete(g, "take m"); !reflect.DeepEqual(got, []string{"take med kit"}) {
+		t.Errorf("Complete(take m) with a light = %q", got)
+	}
+}
+
+func TestLightLeavesWithItem(t *testing.T) {
+	for _, command := range []string{"drop lantern", "put lantern in satchel"} {
+		g, c := darkGame()
+		g.container("satchel").Items = nil
+		c.Execute(g, "light lantern")
+		c.Execute(g, command)
+		if g.Player.Lit != "" || !g.InDarkness() {
+			t.Errorf("after %q Lit = %q, want the light gone", command, g.Player.Lit)
+		}
+	}
+}
@@ -41,7 +41,7 @@ func NewGame() *Game {
 	assets := LoadAssets()
 	sx := float64(screenW) / designW
 	sy := float64(screenH) / designH
-	return &Game{
+	game := &Game{
 		State:    NewGameState(),
 		UI:       NewUIState(),
 		Renderer: NewRenderer(assets, sx, sy),
@@ -50,6 +50,13 @@ func NewGame() *Game {
 		ScaleX:   sx,
 		ScaleY:   sy,
 	}
+	game.State.Events.Subscribe(QuestCompleted, func(state *GameState, event Event) {
+		game.UI.Toast("Quest complete: " + state.Quests[event.ID].Name)
+	})
+	game.State.Events.Subscribe(AchievementUnlocked, func(state *GameState, event Event) {
+		game.UI.Toast("Achievement: " + event.Detail)
+	})
+	return game
 }
 
 func scaleX(v float64) float64 { return v * (float64(screenW) / designW) }
@@ -57,6 +64,7 @@ func scaleY(v float64) float64 { return v * (float64(screenH) / designH) }
 
 func (g *Game) Update() error {
 	g.UI.UpdateInput()
+	g.UI.TickToasts()
 	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
 		ebiten.SetFullscreen(!ebiten.IsFullscreen())
 	}
@@ -78,6 +86,11 @@ func (g *Game) Update() error {
 		g.updateCommandInput()
 	}
 
+	if g.State.Encounter != nil && g.UI.Modal == nil && g.State.Combat == nil {
+		encounter := g.State.Encounter
+		g.UI.Modal = &ModalState{Title: "Encounter", Body: encounter.Title + ": " + encounter.Body, Actions: encounter.Choices}
+	}
+
 	if g.UI.ConfirmMove && g.UI.Modal == nil {
 		if targetRoom, ok := g.State.Rooms[g.UI.MapTarget]; ok {
 			body := "Travel to " + targetRoom.Name + "?"
@@ -101,6 +114,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(g.Renderer.Tokens.Colors["background"])
 	g.drawLayout(screen)
 	g.Renderer.DrawTooltip(screen, g.UI.Tooltip)
+	g.Renderer.DrawToasts(screen, g.UI.Toasts)
 	if g.State.Combat != nil {
 		body := "Press Enter/Space to attack.\nEnemy: " + g.State.Combat.Enemy.Name + " (HP " + itoa(g.State.Combat.Enemy.HP) + ")"
 		combatModal := &ModalState{Title: "Combat", Body: body, Actions: []string{"Fight"}}
@@ -200,14 +214,11 @@ func (g *Game) resolveCombat() {
 	room := g.State.Room()
 	if g.State.Combat.Outcome == "enemy_down" {
 		room.Enemies = removeID(room.Enemies, g.State.Combat.EnemyID)
-		if g.State.Combat.EnemyID == "rival_pirate" && g.State.HasItem("treasure_core") {
-			g.State.Flags["treasureLost"] = true
-		}
-		if g.State.Combat.EnemyID == "rival_pirate" {
-			if quest, ok := g.State.Quests["rival"]; ok {
-				quest.Done = true
-				quest.Outcome = "You beat your rival in the ruins."
-			}
+		g.State.Emit(EnemyDefeated, g.State.Combat.EnemyID, room.ID)
+		g.State.CrewComment("combat")
+		if heal := g.State.CrewHealBonus(); heal > 0 && g.State.Player.HP < g.State.Player.MaxHP {
+			g.State.Player.HP = min(g.State.Player.MaxHP, g.State.Player.HP+heal)
+			g.State.AddLog("Your medic patches your wounds after the fight.", "crew")
 		}
 	}
 	g.State.Combat = nil
@@ -225,9 +236,6 @@ func (g *Game) submitCommand(cmd string) {
 	for _, line := range results {
 		g.State.AddLog(line, "system")
 	}
-	if g.State.Combat == nil {
-		g.State.ResolveQuests()
-	}
 }
 
 func (g *Game) drawLayout(screen *ebiten.Image) {
@@ -362,7 +370,11 @@ func (g *Game) drawTerminalPanel(screen *ebiten.Image, rect Rect) {
 			label = strings.ToUpper(exit[:1])
 		}
 		chip := Rect{X: chipX, Y: sugY, W: chipW, H: btnH}
-		if g.Renderer.DrawChip(screen, chip, label, "neutral", *g.UI) {
+		variant := "neutral"
+		if g.State.ExitBarred(g.State.Player.Location, exit) {
+			variant = "warn"
+		}
+		if g.Renderer.DrawChip(screen, chip, label, variant, *g.UI) {
 			g.submitCommand("go " + exit)
 		}
 		chipX += chipW + chipGap
@@ -381,6 +393,10 @@ func (g *Game) drawVitalsPanel(screen *ebiten.Image, rect Rect) {
 		text.Draw(screen, line, g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
 		y += lineH
 	}
+	for _, line := range wrapText(strings.TrimSpace(g.State.ConditionReport()+"  "+g.State.LightReport()), maxW, g.Renderer.Face) {
+		text.Draw(screen, line, g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+		y += lineH
+	}
 	fruit := "None"
 	if g.State.Player.ActiveFruit != "" {
 		fruit = g.State.Items[g.State.Player.ActiveFruit].Name
@@ -422,9 +438,20 @@ func (g *Game) drawShipStatusPanel(screen *ebiten.Image, rect Rect) {
 		text.Draw(screen, line, g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
 		y += lineH
 	}
-	text.Draw(screen, "Day "+itoa(g.State.Day)+"  "+itoa(g.State.TimeOfDay)+":00", g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+	text.Draw(screen, "Day "+itoa(g.State.Day)+"  "+itoa(g.State.TimeOfDay)+":00  "+titleCase(g.State.Weather), g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
 	y += lineH
-	text.Draw(screen, "$"+itoa(g.State.Money)+"  Wanted "+itoa(g.State.Wanted)+"  Morale "+itoa(g.State.Morale), g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+	text.Draw(screen, "$"+itoa(g.State.Money)+"  Wanted "+itoa(g.State.Wanted())+"  Morale "+itoa(g.State.Morale), g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+	y += lineH
+	for _, line := range wrapText(g.State.HeatReport(), maxW, g.Renderer.Face) {
+		text.Draw(screen, line, g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+		y += lineH
+	}
+	for _, line := range strings.Split(g.State.ShipReport(), "\n") {
+		for _, wrapped := range wrapText(line, maxW, g.Renderer.Face) {
+			text.Draw(screen, wrapped, g.Renderer.Face, int(content.X), int(y), g.Renderer.Tokens.Colors["text"])
+			y += lineH
+		}
+	}
 }
 
 func (g *Game) drawInventoryPanel(screen *ebiten.Image, rect Rect) {
@@ -454,13 +481,14 @@ func (g *Game) drawInventoryPanel(screen *ebiten.Image, rect Rect) {
 	listY := searchRect.Y + searchH + scaleY(8)
 	rowH := scaleY(32)
 	rows := 0
-	for _, itemID := range g.State.Player.Inventory {
+	for _, itemID := range stackIDs(g.State.Player.Inventory) {
 		item := g.State.Items[itemID]
 		if g.UI.InventoryQ != "" && !strings.Contains(strings.ToLower(item.Name), strings.ToLower(g.UI.InventoryQ)) {
 			continue
 		}
+		count := countID(g.State.Player.Inventory, itemID)
 		rowRect := Rect{X: content.X, Y: listY, W: content.W, H: rowH - 4}
-		if g.Renderer.DrawListRow(screen, rowRect, item.Name, "×"+itoa(item.Slots), false, *g.UI) {
+		if g.Renderer.DrawListRow(screen, rowRect, g.State.stackName(itemID, count), "×"+itoa(item.Slots*count), false, *g.UI) {
 			g.UI.SelectedItem = itemID
 			g.UI.Modal = &ModalState{Title: item.Name, Body: item.Desc, Actions: []string{"Use", "Equip", "Drop", "Close"}}
 		}
@@ -471,6 +499,18 @@ func (g *Game) drawInventoryPanel(screen *ebiten.Image, rect Rect) {
 		}
 	}
 	text.Draw(screen, "Slots "+itoa(g.State.InventorySlots())+" / "+itoa(g.State.Player.MaxSlots), g.Renderer.Face, int(content.X), int(rect.Y+rect.H-scaleY(12)), g.Renderer.Tokens.Colors["text"])
+	bookRect := Rect{X: content.X + content.W - scaleX(84), Y: rect.Y + rect.H - scaleY(36), W: scaleX(84), H: scaleY(28)}
+	if g.Renderer.DrawButton(screen, bookRect, "Recipes", "ghost", *g.UI) {
+		g.openRecipeBook()
+	}
+}
+
+func (g *Game) openRecipeBook() {
+	actions := []string{}
+	for _, recipe := range g.State.ReadyRecipes() {
+		actions = append(actions, "Craft "+recipe.Name)
+	}
+	g.UI.Modal = &ModalState{Title: "Recipe Book", Body: g.State.RecipeBook(), Actions: append(actions, "Close")}
 }
 
 func (g *Game) drawMapPanel(screen *ebiten.Image, rect Rect) {
@@ -517,6 +557,19 @@ func (g *Game) drawLocalMap(screen *ebiten.Image, rect Rect, fog bool) {
 	nodeScale := scaleX(36)
 	nodeSize := scaleX(16)
 	halfNode := nodeSize / 2
+	for dir, dest := range room.Exits {
+		next := g.State.Rooms[dest]
+		if next == nil || next.Island != room.Island {
+			continue
+		}
+		lineColor := g.Renderer.Tokens.Colors["border"]
+		if g.State.ExitBarred(room.ID, dir) {
+			lineColor = g.Renderer.Tokens.Colors["danger"]
+		}
+		nx := centerX + float64(next.CoordX-room.CoordX)*nodeScale
+		ny := centerY + float64(next.CoordY-room.CoordY)*nodeScale
+		vector.StrokeLine(screen, float32(centerX), float32(centerY), float32(nx), float32(ny), 1, lineColor, false)
+	}
 	if g.UI.MapTarget != "" && g.State.Rooms[g.UI.MapTarget] != nil {
 		pathDirs := PathCommands(g.State.Rooms, room.ID, g.UI.MapTarget)
 		currentID := room.ID
@@ -581,7 +634,7 @@ func (g *Game) drawWorldMap(screen *ebiten.Image, rect Rect) {
 		x2 := rect.X + to.X
 		y2 := rect.Y + to.Y
 		lineColor := g.Renderer.Tokens.Colors["border"]
-		if route.To == "Navy Bastion" && g.State.Wanted >= 3 {
+		if route.To == "Navy Bastion" && g.State.Wanted() >= 3 {
 			lineColor = g.Renderer.Tokens.Colors["danger"]
 		}
 		vector.StrokeLine(screen, float32(x1), float32(y1), float32(x2), float32(y2), 2, lineColor, false)
@@ -590,8 +643,17 @@ func (g *Game) drawWorldMap(screen *ebiten.Image, rect Rect) {
 	worldNodeHalf := worldNodeSize / 2
 	for _, node := range g.WorldMap.Nodes {
 		nodeRect := Rect{X: rect.X + node.X - worldNodeHalf, Y: rect.Y + node.Y - worldNodeHalf, W: worldNodeSize, H: worldNodeSize}
-		drawRoundedRect(screen, nodeRect, scaleX(10), g.Renderer.Tokens.Colors["surface2"])
-		text.Draw(screen, node.Name, g.Renderer.Small, int(nodeRect.X+scaleX(14)), int(nodeRect.Y+scaleY(6)), g.Renderer.Tokens.Colors["textMuted"])
+		nodeColor := g.Renderer.Tokens.Colors["surface2"]
+		label := node.Name
+		if heat := g.State.Heat[node.Island]; heat > 0 {
+			label += " (heat " + itoa(heat) + ")"
+			nodeColor = g.Renderer.Tokens.Colors["warn"]
+			if heat >= 3 {
+				nodeColor = g.Renderer.Tokens.Colors["danger"]
+			}
+		}
+		drawRoundedRect(screen, nodeRect, scaleX(10), nodeColor)
+		text.Draw(screen, label, g.Renderer.Small, int(nodeRect.X+scaleX(14)), int(nodeRect.Y+scaleY(6)), g.Renderer.Tokens.Colors["textMuted"])
 		if g.UI.MouseJustUp && pointInRect(float64(g.UI.MouseX), float64(g.UI.MouseY), nodeRect) {
 			g.UI.MapTarget = node.ID
 		}
@@ -605,7 +667,7 @@ func (g *Game) drawWorldMap(screen *ebiten.Image, rect Rect) {
 				if route.Needs != "" {
 					line += " - Needs " + route.Needs
 				}
-				if route.To == "Navy Bastion" && g.State.Wanted >= 3 {
+				if route.To == "Navy Bastion" && g.State.Wanted() >= 3 {
 					line += " - Locked (Wanted)"
 				}
 				text.Draw(screen, line, g.Renderer.Small, int(rect.X+scaleX(90)), int(infoY), g.Renderer.Tokens.Colors["text"])
@@ -634,27 +696,7 @@ func (g *Game) equippedLabel(slot string) string {
 }
 
 func (g *Game) buildAutocomplete() []string {
-	base := strings.ToLower(strings.TrimSpace(g.UI.Input))
-	if base == "" {
-		return nil
-	}
-	options := []string{"look", "inventory", "talk", "use", "attack", "take", "drop", "buy", "sell", "save", "load", "help"}
-	room := g.State.Room()
-	for exit := range room.Exits {
-		options = append(options, "go "+exit)
-	}
-	for _, itemID := range room.Items {
-		options = append(options, "take "+strings.ToLower(g.State.Items[itemID].Name))
-	}
-	for _, npcID := range room.NPCs {
-		options = append(options, "talk "+strings.ToLower(g.State.NPCs[npcID].Name))
-	}
-	for _, option := range options {
-		if strings.HasPrefix(option, base) {
-			return []string{option}
-		}
-	}
-	return nil
+	return g.Cmd.Complete(g.State, g.UI.Input)
 }
 
 func (g *Game) handleModalAction(action string) {
@@ -672,6 +714,12 @@ func (g *Game) handleModalAction(action string) {
 		}
 	case "Combat":
 		return
+	case "Encounter":
+		g.State.ResolveEncounter(action)
+	case "Recipe Book":
+		if name, ok := strings.CutPrefix(action, "Craft "); ok {
+			g.submitCommand("craft " + name)
+		}
 	default:
 		if g.UI.SelectedItem != "" {
 			switch action {
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Consumable struct {
+	ID      string
+	Heal    int
+	Food    int
+	Rest    int
+	Morale  int
+	Cures   []string
+	Status  string
+	Hours   int
+	Message string
+}
+
+type Status struct {
+	ID    string
+	Name  string
+	Mods  map[string]int
+	Drain int
+}
+
+func Consumables() map[string]Consumable {
+	return map[string]Consumable{
+		"medkit":    {ID: "medkit", Heal: 6, Cures: []string{"poisoned"}, Message: "You patch yourself up."},
+		"balm":      {ID: "balm", Heal: 3, Cures: []string{"poisoned"}, Message: "The balm cools the sting. Whatever was in your blood loosens its grip."},
+		"fire_balm": {ID: "fire_balm", Heal: 5, Cures: []string{"poisoned"}, Status: "fired_up", Hours: 6, Message: "The fire balm burns, then roars. You feel ready to wrestle a reef beast."},
+		"rum":       {ID: "rum", Rest: 2, Morale: 1, Status: "drunk", Hours: 3, Message: "You take a swig. Courage bubbles up."},
+		"hardtack":  {ID: "hardtack", Food: 12, Message: "You gnaw through the biscuit. Dry, but it sits well."},
+	}
+}
+
+func Statuses() map[string]Status {
+	return map[string]Status{
+		"drunk":    {ID: "drunk", Name: "Tipsy", Mods: map[string]int{"charm": 1, "wits": -2}},
+		"poisoned": {ID: "poisoned", Name: "Poisoned", Mods: map[string]int{"grit": -1}, Drain: 1},
+		"fired_up": {ID: "fired_up", Name: "Fired Up", Mods: map[string]int{"grit": 1}},
+		"well_fed": {ID: "well_fed", Name: "Well Fed", Mods: map[string]int{"grit": 1, "wits": 1}},
+	}
+}
+
+func hungerLevel(hunger int) int {
+	switch {
+	case hunger >= 28:
+		return 2
+	case hunger >= 16:
+		return 1
+	}
+	return 0
+}
+
+func fatigueLevel(fatigue int) int {
+	switch {
+	case fatigue >= 30:
+		return 2
+	case fatigue >= 18:
+		return 1
+	}
+	return 0
+}
+
+func (g *GameState) NeedsPenalty() int {
+	return hungerLevel(g.Player.Hunger) + fatigueLevel(g.Player.Fatigue)
+}
+
+func (g *GameState) StatusBonus(stat string) int {
+	bonus := 0
+	for id := range g.Player.Status {
+		bonus += Statuses()[id].Mods[stat]
+	}
+	return bonus
+}
+
+func (g *GameState) AddStatus(id string, hours int) {
+	if g.Player.Status == nil {
+		g.Player.Status = map[string]int{}
+	}
+	g.Player.Status[id] += hours
+}
+
+func (g *GameState) TickNeeds() {
+	p := &g.Player
+	hunger, fatigue := hungerLevel(p.Hunger), fatigueLevel(p.Fatigue)
+	p.Hunger++
+	p.Fatigue++
+	if next := hungerLevel(p.Hunger); next > hunger {
+		g.AddLog([]string{"", "Your stomach growls. You're hungry.", "You're starving. Your hands shake."}[next], "event")
+	}
+	if next := fatigueLevel(p.Fatigue); next > fatigue {
+		g.AddLog([]string{"", "You're tired. A bunk would help.", "You're exhausted. Your eyes keep closing."}[next], "event")
+	}
+	drain := 0
+	if hungerLevel(p.Hunger) == 2 {
+		drain++
+	}
+	for id := range p.Status {
+		drain += Statuses()[id].Drain
+		p.Status[id]--
+		if p.Status[id] <= 0 {
+			delete(p.Status, id)
+			g.AddLog(fmt.Sprintf("You're no longer %s.", strings.ToLower(Statuses()[id].Name)), "event")
+		}
+	}
+	p.HP = max(min(p.HP, 1), p.HP-drain)
+}
+
+func (g *GameState) Consume(name string) string {
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	effect, ok := Consumables()[itemID]
+	if !ok {
+		return g.refuse(fmt.Sprintf("You can't eat or drink the %s.", g.Items[itemID].Name))
+	}
+	if effect.Status == "drunk" && g.Player.Status["drunk"] >= 6 {
+		return g.refuse("One more and you'll be swimming home. Maybe later.")
+	}
+	p := &g.Player
+	p.Inventory = removeOne(p.Inventory, itemID)
+	if effect.Heal > 0 {
+		p.HP = min(p.MaxHP, p.HP+effect.Heal+g.CrewHealBonus())
+	}
+	p.Hunger = max(0, p.Hunger-effect.Food)
+	p.Fatigue = max(0, p.Fatigue-effect.Rest)
+	g.Morale += effect.Morale
+	for _, status := range effect.Cures {
+		delete(p.Status, status)
+	}
+	if effect.Status != "" {
+		g.AddStatus(effect.Status, effect.Hours)
+	}
+	return effect.Message
+}
+
+func (g *GameState) Eat(name string) string {
+	if name != "" {
+		return g.Consume(name)
+	}
+	room := g.Room()
+	switch {
+	case room.ID == "tavern":
+		cost := g.Price(6, "")
+		if g.Money < cost {
+			return g.refuse(fmt.Sprintf("A bowl of chowder costs %d coins. You don't have enough.", cost))
+		}
+		g.Money -= cost
+		g.Player.Hunger = 0
+		g.AddStatus("well_fed", 4)
+		return fmt.Sprintf("You wolf down a bowl of chowder for %d coins. Well fed.", cost)
+	case room.Island == "Ship":
+		if g.Ship.Food == 0 {
+			return g.refuse("The galley is bare. Provision at a dock.")
+		}
+		g.Ship.Food--
+		g.Player.Hunger = 0
+		return "The cook ladles you a bowl from the ship's stores. (-1 food)"
+	}
+	for _, itemID := range g.Player.Inventory {
+		if Consumables()[itemID].Food > 0 {
+			return g.Consume(itemID)
+		}
+	}
+	return g.refuse("There's nothing to eat here. Try the tavern, the galley or a biscuit.")
+}
+
+func (g *GameState) Rest() string {
+	room := g.Room()
+	if g.Combat != nil || len(room.Enemies) > 0 {
+		return g.refuse("Not with enemies about.")
+	}
+	line := "You sleep in your cabin, rocked by the harbor swell."
+	switch room.ID {
+	case "ship_cabin":
+	case "tavern":
+		cost := g.Price(10, "")
+		if g.Money < cost {
+			return g.refuse(fmt.Sprintf("A bunk upstairs costs %d coins. You don't have enough.", cost))
+		}
+		g.Money -= cost
+		line = fmt.Sprintf("You rent a bunk above the tavern for %d coins and sleep like a stone.", cost)
+	default:
+		return g.refuse("You can't rest easy here. Try the tavern or your cabin.")
+	}
+	for i := 0; i < 8; i++ {
+		g.AdvanceTime()
+	}
+	g.Player.Fatigue = 0
+	g.Player.HP = min(g.Player.MaxHP, g.Player.HP+g.Player.MaxHP/2)
+	delete(g.Player.Status, "drunk")
+	return line + " You wake at " + fmt.Sprintf("%02d:00", g.TimeOfDay) + ", rested."
+}
+
+func (g *GameState) ConditionReport() string {
+	hunger := []string{"Fed", "Hungry", "Starving"}[hungerLevel(g.Player.Hunger)]
+	fatigue := []string{"Rested", "Tired", "Exhausted"}[fatigueLevel(g.Player.Fatigue)]
+	parts := []string{hunger, fatigue}
+	ids := make([]string, 0, len(g.Player.Status))
+	for id := range g.Player.Status {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s (%dh)", Statuses()[id].Name, g.Player.Status[id]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type NPCRecord struct {
+	Attitude int
+	Met      bool
+	Memory   []string
+}
+
+type ScheduleStop struct {
+	Hour int
+	Room string
+}
+
+type NPCRoutine struct {
+	Schedule []ScheduleStop
+	Alarm    bool
+	Warns    bool
+	Gossip   bool
+}
+
+func NPCRoutines() map[string]NPCRoutine {
+	return map[string]NPCRoutine{
+		"officer":   {Alarm: true},
+		"dockhand":  {Schedule: []ScheduleStop{{Hour: 7, Room: "dock"}, {Hour: 20, Room: "tavern"}}, Warns: true, Gossip: true},
+		"herbalist": {Schedule: []ScheduleStop{{Hour: 7, Room: "jungle_grove"}, {Hour: 19, Room: "ember_village"}}, Warns: true},
+		"gadgeteer": {Schedule: []ScheduleStop{{Hour: 8, Room: "market_lane"}, {Hour: 21, Room: "tavern"}}},
+		"bartender": {Warns: true, Gossip: true},
+		"broker":    {Gossip: true},
+		"vendor":    {Gossip: true},
+		"trader":    {Gossip: true},
+	}
+}
+
+func MemoryLines() map[string]string {
+	return map[string]string{
+		"helped":     "'I haven't forgotten what you did for me.'",
+		"bribed":     "'Back with another donation, Captain?'",
+		"threatened": "'I remember your threats.'",
+		"crime":      "'I saw what you did. Don't think I didn't.'",
+	}
+}
+
+func dispositionAttitude(disposition string) int {
+	switch disposition {
+	case "hostile":
+		return -3
+	case "friendly":
+		return 3
+	default:
+		return 0
+	}
+}
+
+func (g *GameState) InitNPCs() {
+	g.People = map[string]*NPCRecord{}
+	for id, npc := range g.NPCs {
+		g.People[id] = &NPCRecord{Attitude: dispositionAttitude(npc.Disposition), Memory: []string{}}
+	}
+}
+
+func (g *GameState) person(npcID string) *NPCRecord {
+	record, ok := g.People[npcID]
+	if !ok {
+		record = &NPCRecord{Attitude: dispositionAttitude(g.NPCs[npcID].Disposition), Memory: []string{}}
+		g.People[npcID] = record
+	}
+	return record
+}
+
+func (g *GameState) Mood(npcID string) string {
+	record := g.person(npcID)
+	switch {
+	case record.Attitude <= -3:
+		return "hostile"
+	case record.Attitude >= 3:
+		return "friendly"
+	case record.Met:
+		return "met"
+	default:
+		return "neutral"
+	}
+}
+
+func (g *GameState) ShiftAttitude(npcID string, delta int) {
+	record := g.person(npcID)
+	record.Attitude = max(-10, min(10, record.Attitude+delta))
+}
+
+func (g *GameState) SetMood(npcID string, mood string) {
+	record := g.person(npcID)
+	switch mood {
+	case "hostile":
+		record.Attitude = min(record.Attitude, -3)
+	case "friendly":
+		record.Attitude = max(record.Attitude, 3)
+	default:
+		record.Attitude = dispositionAttitude(mood)
+	}
+}
+
+func (g *GameState) Remember(npcID string, event string) {
+	record := g.person(npcID)
+	record.Memory = append(removeID(record.Memory, event), event)
+	if len(record.Memory) > 5 {
+		record.Memory = record.Memory[len(record.Memory)-5:]
+	}
+}
+
+func (g *GameState) Helped(npcID string) {
+	g.ShiftAttitude(npcID, 2)
+	g.Remember(npcID, "helped")
+}
+
+func (g *GameState) memoryLine(npcID string) string {
+	memory := g.person(npcID).Memory
+	if len(memory) == 0 {
+		return ""
+	}
+	return MemoryLines()[memory[len(memory)-1]]
+}
+
+func (g *GameState) WitnessCrime() {
+	room := g.Room()
+	if room == nil {
+		return
+	}
+	for _, npcID := range room.NPCs {
+		if contains(g.Companions, npcID) {
+			continue
+		}
+		g.Remember(npcID, "crime")
+		if g.NPCs[npcID].Faction != "smugglers" {
+			g.ShiftAttitude(npcID, -1)
+		}
+	}
+}
+
+func (g *GameState) scheduledRoom(routine NPCRoutine) string {
+	stops := routine.Schedule
+	current := stops[len(stops)-1].Room
+	for _, stop := range stops {
+		if g.TimeOfDay >= stop.Hour {
+			current = stop.Room
+		}
+	}
+	return current
+}
+
+func (g *GameState) FollowSchedules() {
+	for npcID, routine := range NPCRoutines() {
+		if len(routine.Schedule) == 0 || contains(g.Companions, npcID) {
+			continue
+		}
+		dest := g.Rooms[g.scheduledRoom(routine)]
+		if dest == nil || contains(dest.NPCs, npcID) {
+			continue
+		}
+		name := g.NPCs[npcID].Name
+		for roomID, room := range g.Rooms {
+			if !contains(room.NPCs, npcID) {
+				continue
+			}
+			room.NPCs = removeID(room.NPCs, npcID)
+			if roomID == g.Player.Location {
+				g.AddLog(fmt.Sprintf("The %s heads off toward the %s.", name, dest.Name), "event")
+			}
+		}
+		dest.NPCs = append(dest.NPCs, npcID)
+		if dest.ID == g.Player.Location {
+			g.AddLog(fmt.Sprintf("The %s wanders in.", name), "event")
+		}
+	}
+}
+
+func (g *GameState) NPCReactions() {
+	room := g.Room()
+	if room == nil {
+		return
+	}
+	routines := NPCRoutines()
+	for _, npcID := range room.NPCs {
+		routine := routines[npcID]
+		name := g.NPCs[npcID].Name
+		mood := g.Mood(npcID)
+		if routine.Alarm && mood == "hostile" && g.Wanted() >= 2 && len(room.Enemies) == 0 && rand.Float64() < 0.4 {
+			room.Enemies = append(room.Enemies, "navy_patrol")
+			g.AddLog(fmt.Sprintf("The %s spots you and blows a whistle. A patrol comes running!", name), "event")
+		}
+		if routine.Warns && mood == "friendly" {
+			if dir := g.dangerNearby(room); dir != "" {
+				g.AddLog(fmt.Sprintf("%s: 'Careful, something nasty is lurking to the %s.'", name, dir), "crew")
+			}
+		}
+	}
+}
+
+func (g *GameState) dangerNearby(room *Room) string {
+	for _, dir := range exitKeys(room.Exits) {
+		if next := g.Rooms[room.Exits[dir]]; next != nil && len(next.Enemies) > 0 {
+			return dir
+		}
+	}
+	return ""
+}
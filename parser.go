@@ -0,0 +1,195 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+var articles = map[string]bool{"the": true, "a": true, "an": true, "some": true, "my": true, "this": true, "that": true}
+
+var prepositions = map[string]bool{"with": true, "to": true, "at": true, "from": true, "on": true, "in": true, "into": true}
+
+type Parsed struct {
+	Verb   string
+	Object string
+	Prep   string
+	Target string
+	Count  int
+	All    bool
+	Except []string
+}
+
+func ParseCommand(words []string) Parsed {
+	parsed := Parsed{}
+	if len(words) == 0 {
+		return parsed
+	}
+	parsed.Verb = words[0]
+	rest := words[1:]
+	if parsed.Verb == "pick" && len(rest) > 0 && rest[0] == "up" {
+		parsed.Verb, rest = "take", rest[1:]
+	}
+	if parsed.Verb == "look" && len(rest) > 0 && rest[0] == "at" {
+		parsed.Verb, rest = "examine", rest[1:]
+	}
+	if parsed.Verb == "look" && len(rest) > 0 && (rest[0] == "in" || rest[0] == "inside") {
+		parsed.Verb, rest = "search", rest[1:]
+	}
+	object, target := []string{}, []string{}
+	excepting := false
+	for _, word := range rest {
+		last := len(parsed.Except) - 1
+		switch {
+		case (word == "except" || word == "but") && parsed.Prep == "" && len(object) > 0 && object[0] == "all":
+			excepting = true
+			parsed.Except = append(parsed.Except, "")
+		case excepting && word == "and":
+			parsed.Except = append(parsed.Except, "")
+		case excepting && articles[word] && parsed.Except[last] == "":
+		case excepting:
+			parsed.Except[last] = strings.TrimSpace(parsed.Except[last] + " " + word)
+		case articles[word] && ((parsed.Prep == "" && len(object) == 0) || (parsed.Prep != "" && len(target) == 0)):
+		case prepositions[word] && parsed.Prep == "":
+			parsed.Prep = word
+		case parsed.Prep == "":
+			object = append(object, word)
+		default:
+			target = append(target, word)
+		}
+	}
+	object = parsed.quantity(object)
+	parsed.Object = strings.Join(object, " ")
+	parsed.Target = strings.Join(target, " ")
+	if parsed.Object == "" && !parsed.All {
+		parsed.Object, parsed.Target = parsed.Target, ""
+	}
+	return parsed
+}
+
+func (parsed *Parsed) quantity(words []string) []string {
+	if len(words) == 0 {
+		return words
+	}
+	if n, err := strconv.Atoi(words[0]); err == nil && n > 0 {
+		parsed.Count, words = n, words[1:]
+	} else if words[0] == "all" || words[0] == "everything" {
+		parsed.All, words = true, words[1:]
+	}
+	for len(words) > 0 && (articles[words[0]] || words[0] == "of") {
+		words = words[1:]
+	}
+	return words
+}
+
+func (g *GameState) scopeIDs(scope string) []string {
+	room := g.Room()
+	if room == nil {
+		return nil
+	}
+	switch scope {
+	case "room":
+		return g.visibleItems()
+	case "inventory":
+		return g.Player.Inventory
+	case "hold":
+		return g.Ship.Hold
+	case "hidden":
+		return g.Ship.Hidden
+	case "npc":
+		return room.NPCs
+	case "companion":
+		return g.Companions
+	case "enemy":
+		return room.Enemies
+	case "stock":
+		ids := []string{}
+		for _, npcID := range g.Merchants() {
+			ids = append(ids, g.stockIDs(npcID)...)
+		}
+		return ids
+	case "exit":
+		return exitKeys(room.Exits)
+	case "container":
+		return g.reachableContainers()
+	case "near":
+		ids := append([]string{}, g.Player.Inventory...)
+		ids = append(ids, g.visibleItems()...)
+		ids = append(ids, room.NPCs...)
+		return append(ids, room.Enemies...)
+	}
+	return nil
+}
+
+func (g *GameState) describe(id string) (string, string) {
+	if item, ok := g.Items[id]; ok {
+		return item.Name, item.Desc
+	}
+	if npc, ok := g.NPCs[id]; ok {
+		return npc.Name, npc.Desc
+	}
+	if enemy, ok := g.Enemies[id]; ok {
+		return enemy.Name, enemy.Desc
+	}
+	return id, ""
+}
+
+func wordMatches(query string, words []string) bool {
+	for _, word := range words {
+		if word == query || strings.TrimSuffix(query, "s") == word {
+			return true
+		}
+	}
+	return false
+}
+
+func nameWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '\'' || r == '-' || r == ',' || r == '.'
+	})
+}
+
+func (g *GameState) MatchNames(phrase string, ids []string) []string {
+	phrase = strings.ToLower(strings.TrimSpace(phrase))
+	query := nameWords(phrase)
+	if len(query) == 0 {
+		return nil
+	}
+	matches := []string{}
+	for _, id := range ids {
+		if contains(matches, id) {
+			continue
+		}
+		name, desc := g.describe(id)
+		if strings.ToLower(name) == phrase || id == phrase {
+			return []string{id}
+		}
+		words := append(nameWords(name), nameWords(id)...)
+		if !wordMatches(query[len(query)-1], words) {
+			continue
+		}
+		adjectives := append(words, nameWords(desc)...)
+		matched := true
+		for _, word := range query[:len(query)-1] {
+			if !wordMatches(word, adjectives) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, id)
+		}
+	}
+	return matches
+}
+
+func (g *GameState) whichDoYouMean(ids []string) string {
+	names := []string{}
+	for _, id := range ids {
+		name, _ := g.describe(id)
+		names = append(names, "the "+name)
+	}
+	if len(names) == 2 {
+		return "Which do you mean, " + names[0] + " or " + names[1] + "?"
+	}
+	return "Which do you mean, " + strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1] + "?"
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	cases := []struct {
+		input string
+		want  Parsed
+	}{
+		{"take the rope", Parsed{Verb: "take", Object: "rope"}},
+		{"pick up a brass key", Parsed{Verb: "take", Object: "brass key"}},
+		{"use rum on the broker", Parsed{Verb: "use", Object: "rum", Prep: "on", Target: "broker"}},
+		{"take pearl from sea chest", Parsed{Verb: "take", Object: "pearl", Prep: "from", Target: "sea chest"}},
+		{"look at the cook", Parsed{Verb: "examine", Object: "cook"}},
+		{"drop 3 pearls", Parsed{Verb: "drop", Object: "pearls", Count: 3}},
+		{"take all except the rope and flare", Parsed{Verb: "take", All: true, Except: []string{"rope", "flare"}}},
+		{"go to north", Parsed{Verb: "go", Object: "north", Prep: "to"}},
+	}
+	for _, c := range cases {
+		got := ParseCommand(strings.Fields(c.input))
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseCommand(%q) = %+v, want %+v", c.input, got, c.want)
+		}
+	}
+}
+
+func TestMatchNames(t *testing.T) {
+	g := NewGameState()
+	ids := []string{"rope", "chest_key", "stone_key", "cipher_lens"}
+	cases := []struct {
+		phrase string
+		want   []string
+	}{
+		{"lens", []string{"cipher_lens"}},
+		{"brass key", []string{"chest_key"}},
+		{"key", []string{"chest_key", "stone_key"}},
+		{"stone key", []string{"stone_key"}},
+		{"coil of rope", []string{"rope"}},
+		{"ropes", []string{"rope"}},
+		{"silver key", []string{}},
+	}
+	for _, c := range cases {
+		if got := g.MatchNames(c.phrase, ids); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("MatchNames(%q) = %v, want %v", c.phrase, got, c.want)
+		}
+	}
+}
+
+func TestPronounsAndDisambiguation(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	g.Room().Items = append(g.Room().Items, "stone_key")
+	got := c.Execute(g, "take key")
+	if len(got) != 1 || got[0] != "Which do you mean, the Small Brass Key or the Stone Key?" {
+		t.Fatalf("take key = %q, want a which-do-you-mean prompt", got)
+	}
+	c.Execute(g, "the brass one")
+	if !contains(g.Player.Inventory, "chest_key") || contains(g.Player.Inventory, "stone_key") {
+		t.Fatalf("answering the prompt took %v, want the brass key", g.Player.Inventory)
+	}
+	c.Execute(g, "drop it")
+	if contains(g.Player.Inventory, "chest_key") {
+		t.Errorf("drop it left %v in the inventory, want the brass key dropped", g.Player.Inventory)
+	}
+	if got := NewCommandProcessor().Execute(g, "examine it"); got[0] != "I'm not sure what you mean by 'it'." {
+		t.Errorf("examine it with nothing mentioned = %q", got)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+//go:embed assets/puzzles.json
+var builtinPuzzles []byte
+
+type Puzzle struct {
+	ID       string
+	Room     string
+	Kind     string
+	Answers  []string
+	Item     string
+	Needs    string
+	Controls []string
+	Solution map[string]bool
+	Sequence []string
+	Flag     string
+	Reward   []string
+	Success  string
+	Failure  string
+	Hints    []string
+}
+
+type PuzzleState struct {
+	Controls map[string]bool
+	Entered  []string
+}
+
+func LoadPuzzles() ([]Puzzle, error) {
+	var puzzles []Puzzle
+	err := loadContent("puzzles.json", builtinPuzzles, &puzzles)
+	valid, skipped := []Puzzle{}, []string{}
+	for _, puzzle := range puzzles {
+		if problem := puzzle.problem(); problem != "" {
+			skipped = append(skipped, puzzle.ID+" "+problem)
+			continue
+		}
+		valid = append(valid, puzzle)
+	}
+	if err == nil && len(skipped) > 0 {
+		err = fmt.Errorf("puzzles.json: skipped %s", strings.Join(skipped, "; "))
+	}
+	return valid, err
+}
+
+func (puzzle Puzzle) problem() string {
+	switch {
+	case puzzle.ID == "" || puzzle.Room == "" || puzzle.Flag == "":
+		return "needs an ID, Room and Flag"
+	case len(puzzle.Hints) == 0:
+		return "has no hints"
+	}
+	switch puzzle.Kind {
+	case "riddle":
+		if len(puzzle.Answers) == 0 && puzzle.Item == "" {
+			return "has no answers"
+		}
+	case "item":
+		if puzzle.Item == "" {
+			return "has no item"
+		}
+	case "levers":
+		if len(puzzle.Controls) == 0 {
+			return "has no controls"
+		}
+	case "runes":
+		if len(puzzle.Sequence) == 0 {
+			return "has no sequence"
+		}
+		for _, control := range puzzle.Sequence {
+			if !contains(puzzle.Controls, control) {
+				return "has a sequence step that isn't one of its controls"
+			}
+		}
+	default:
+		return fmt.Sprintf("has an unknown kind %q", puzzle.Kind)
+	}
+	return ""
+}
+
+func (g *GameState) roomPuzzles() []Puzzle {
+	puzzles := []Puzzle{}
+	for _, puzzle := range g.PuzzleDefs {
+		if puzzle.Room == g.Player.Location {
+			puzzles = append(puzzles, puzzle)
+		}
+	}
+	sort.Slice(puzzles, func(i, j int) bool { return puzzles[i].ID < puzzles[j].ID })
+	return puzzles
+}
+
+func (g *GameState) puzzleState(roomID string) *PuzzleState {
+	state, ok := g.Puzzles[roomID]
+	if !ok {
+		state = &PuzzleState{Controls: map[string]bool{}, Entered: []string{}}
+		g.Puzzles[roomID] = state
+	}
+	return state
+}
+
+func (g *GameState) solvePuzzle(puzzle Puzzle) string {
+	g.SetFlag(puzzle.Flag)
+	if len(puzzle.Reward) > 0 {
+		room := g.Room()
+		room.Items = append(room.Items, puzzle.Reward...)
+		return puzzle.Success + " Inside: " + g.ListItemNames(puzzle.Reward) + "."
+	}
+	return puzzle.Success
+}
+
+func normalizeSpeech(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	return strings.Trim(text, "'\".!?,")
+}
+
+func (g *GameState) Say(text string) string {
+	spoken := normalizeSpeech(text)
+	if spoken == "" {
+		return g.refuse("Say what?")
+	}
+	heard := false
+	for _, puzzle := range g.roomPuzzles() {
+		if puzzle.Kind != "riddle" || g.Flags[puzzle.Flag] {
+			continue
+		}
+		heard = true
+		if contains(puzzle.Answers, spoken) {
+			return g.solvePuzzle(puzzle)
+		}
+		return g.refuse(puzzle.Failure)
+	}
+	if !heard {
+		return fmt.Sprintf("You say '%s'. No one seems to care.", text)
+	}
+	return "Nothing answers."
+}
+
+func (g *GameState) UsePuzzleItem(itemID string) string {
+	for _, puzzle := range g.roomPuzzles() {
+		if puzzle.Item == itemID && !g.Flags[puzzle.Flag] {
+			return g.solvePuzzle(puzzle)
+		}
+	}
+	return ""
+}
+
+func (g *GameState) Operate(name string) string {
+	controls := []string{}
+	owners := map[string]Puzzle{}
+	for _, puzzle := range g.roomPuzzles() {
+		for _, control := range puzzle.Controls {
+			controls = append(controls, control)
+			owners[control] = puzzle
+		}
+	}
+	matches := g.MatchNames(normalizeSpeech(name), controls)
+	if len(matches) == 0 {
+		return g.refuse("There's nothing like that to work here.")
+	}
+	if len(matches) > 1 {
+		return g.refuse(g.whichDoYouMean(matches))
+	}
+	control, puzzle := matches[0], owners[matches[0]]
+	if g.Flags[puzzle.Flag] {
+		return g.refuse("It's already done its work.")
+	}
+	if puzzle.Kind == "runes" {
+		return g.pressRune(puzzle, control)
+	}
+	state := g.puzzleState(puzzle.Room)
+	state.Controls[control] = !state.Controls[control]
+	message := fmt.Sprintf("Clunk. The %s is now %s.", control, onOff(control, state.Controls[control]))
+	for _, wanted := range puzzle.Controls {
+		if state.Controls[wanted] != puzzle.Solution[wanted] {
+			return message
+		}
+	}
+	return message + " " + g.solvePuzzle(puzzle)
+}
+
+func onOff(control string, on bool) string {
+	if strings.Contains(control, "plate") {
+		if on {
+			return "pressed"
+		}
+		return "raised"
+	}
+	if on {
+		return "down"
+	}
+	return "up"
+}
+
+func (g *GameState) pressRune(puzzle Puzzle, rune string) string {
+	if puzzle.Needs != "" && !g.HasItem(puzzle.Needs) {
+		return g.refuse(fmt.Sprintf("The runes swim before your eyes. You'd need a %s to make sense of them.", g.Items[puzzle.Needs].Name))
+	}
+	state := g.puzzleState(puzzle.Room)
+	state.Entered = append(state.Entered, rune)
+	for i, entered := range state.Entered {
+		if i >= len(puzzle.Sequence) || puzzle.Sequence[i] != entered {
+			state.Entered = []string{}
+			return g.refuse(puzzle.Failure)
+		}
+	}
+	if len(state.Entered) < len(puzzle.Sequence) {
+		return fmt.Sprintf("The %s glows softly.", rune)
+	}
+	state.Entered = []string{}
+	return g.solvePuzzle(puzzle)
+}
+
+func (g *GameState) Hint() string {
+	for _, puzzle := range g.roomPuzzles() {
+		if g.Flags[puzzle.Flag] {
+			continue
+		}
+		if len(puzzle.Hints) == 0 || !g.SkillCheck("wits") {
+			return "You puzzle over it, but nothing clicks yet."
+		}
+		return puzzle.Hints[rand.Intn(len(puzzle.Hints))]
+	}
+	return "Nothing here puzzles you."
+}
+
+func (g *GameState) PuzzleLook() []string {
+	lines := []string{}
+	for _, puzzle := range g.roomPuzzles() {
+		if g.Flags[puzzle.Flag] || (puzzle.Kind != "levers" && puzzle.Kind != "runes") {
+			continue
+		}
+		state := g.puzzleState(puzzle.Room)
+		parts := []string{}
+		for _, control := range puzzle.Controls {
+			if puzzle.Kind == "runes" {
+				parts = append(parts, control)
+			} else {
+				parts = append(parts, fmt.Sprintf("%s (%s)", control, onOff(control, state.Controls[control])))
+			}
+		}
+		lines = append(lines, "Mechanisms: "+strings.Join(parts, ", "))
+	}
+	return lines
+}
@@ -0,0 +1,95 @@
+package main
+
+import "testing"
+
+func TestRiddle(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	g.Player.Location = "ruins_gate"
+	c.Execute(g, "say the stone")
+	if g.Flags["ruinUnlocked"] {
+		t.Fatalf("a wrong answer opened the gate")
+	}
+	c.Execute(g, "say \"The Wild Current!\"")
+	if !g.Flags["ruinUnlocked"] {
+		t.Errorf("the right answer left the gate shut")
+	}
+}
+
+func TestLevers(t *testing.T) {
+	g := NewGameState()
+	c := NewCommandProcessor()
+	g.Player.Location = "ruins_hall"
+	g.Player.Lit = "storm_lantern"
+	g.Player.Inventory = []string{"storm_lantern"}
+	c.Execute(g, "pull tide lever, pull moon lever")
+	if g.Flags["alcoveOpen"] {
+		t.Fatalf("the alcove opened with the moon lever down")
+	}
+	c.Execute(g, "pull moon lever, press pressure plate")
+	if !g.Flags["alcoveOpen"] || !contains(g.Room().Items, "repair_kit") {
+		t.Errorf("solving the levers gave flag %v and items %v", g.Flags["alcoveOpen"], g.Room().Items)
+	}
+}
+
+func TestRunes(t *testing.T) {
+	g := NewGameState()
+	g.Player.Location = "ruins_core"
+	if got := g.Operate("wave rune"); got != "The runes swim before your eyes. You'd need a Cipher Lens to make sense of them." {
+		t.Errorf("runes without the lens = %q", got)
+	}
+	g.Player.Inventory = []string{"cipher_lens"}
+	g.Operate("wave rune")
+	g.Operate("storm rune")
+	if len(g.puzzleState("ruins_core").Entered) != 0 {
+		t.Errorf("a wrong rune didn't reset the sequence")
+	}
+	for _, control := range []string{"wave rune", "moon rune", "sun rune"} {
+		g.Operate(control)
+	}
+	if !g.Flags["runesAligned"] || !contains(g.Room().Items, "sun_coin") {
+		t.Errorf("the right sequence gave flag %v and items %v", g.Flags["runesAligned"], g.Room().Items)
+	}
+}
+
+func TestRunesPastSequence(t *testing.T) {
+	g := NewGameState()
+	puzzle := Puzzle{ID: "short", Room: g.Player.Location, Kind: "runes", Flag: "short", Controls: []string{"a rune"}, Sequence: []string{"a rune"}, Failure: "Wrong."}
+	g.puzzleState(puzzle.Room).Entered = []string{"a rune"}
+	if got := g.pressRune(puzzle, "a rune"); got != "Wrong." {
+		t.Errorf("pressing past the sequence = %q, want a reset", got)
+	}
+}
+
+func TestHintWithoutHints(t *testing.T) {
+	g := NewGameState()
+	g.Player.Wits = 40
+	g.PuzzleDefs = []Puzzle{{ID: "bare", Room: g.Player.Location, Kind: "item", Flag: "bare", Item: "rope"}}
+	if got := g.Hint(); got != "You puzzle over it, but nothing clicks yet." {
+		t.Errorf("Hint() with no hints = %q", got)
+	}
+}
+
+func TestPuzzleProblems(t *testing.T) {
+	cases := []struct {
+		puzzle Puzzle
+		want   string
+	}{
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "item", Item: "rope", Hints: []string{"h"}}, ""},
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "item", Item: "rope"}, "has no hints"},
+		{Puzzle{ID: "p", Room: "r", Kind: "item", Item: "rope", Hints: []string{"h"}}, "needs an ID, Room and Flag"},
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "runes", Controls: []string{"a"}, Hints: []string{"h"}}, "has no sequence"},
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "runes", Controls: []string{"a"}, Sequence: []string{"b"}, Hints: []string{"h"}}, "has a sequence step that isn't one of its controls"},
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "levers", Hints: []string{"h"}}, "has no controls"},
+		{Puzzle{ID: "p", Room: "r", Flag: "f", Kind: "maze", Hints: []string{"h"}}, "has an unknown kind \"maze\""},
+	}
+	for _, c := range cases {
+		if got := c.puzzle.problem(); got != c.want {
+			t.Errorf("problem(%+v) = %q, want %q", c.puzzle, got, c.want)
+		}
+	}
+	puzzles, err := LoadPuzzles()
+	if err != nil || len(puzzles) != 4 {
+		t.Errorf("LoadPuzzles() = %d puzzles, %v; want the 4 built-in ones", len(puzzles), err)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+)
+
+const rivalPace = 8
+
+type RivalState struct {
+	Location  string
+	Fragments []string
+	Loot      []string
+	Clock     int
+	Standoff  int
+	Defeated  bool
+	LastSeen  string
+	SeenAt    int
+	SeenWith  int
+}
+
+func NewRival() RivalState {
+	return RivalState{Location: "sky_shrine", Fragments: []string{}, Loot: []string{}}
+}
+
+func glyphFragments() []string {
+	return []string{"glyph_frag_1", "glyph_frag_2", "glyph_frag_3"}
+}
+
+func (g *GameState) hourStamp() int {
+	return g.Day*24 + g.TimeOfDay
+}
+
+func (g *GameState) PlaceRival() {
+	if g.Rival.Defeated {
+		return
+	}
+	if room := g.Rooms[g.Rival.Location]; room != nil && !contains(room.Enemies, "rival_pirate") {
+		room.Enemies = append(room.Enemies, "rival_pirate")
+	}
+}
+
+func (g *GameState) rivalTarget() string {
+	if len(g.Rival.Fragments) == len(glyphFragments()) {
+		return "ruins_core"
+	}
+	best, bestDist := "", -1
+	for _, fragID := range glyphFragments() {
+		for roomID, room := range g.Rooms {
+			if !contains(room.Items, fragID) {
+				continue
+			}
+			path := g.roomPath(g.Rival.Location, roomID)
+			if path == nil {
+				continue
+			}
+			if dist := len(path); bestDist < 0 || dist < bestDist || (dist == bestDist && roomID < best) {
+				best, bestDist = roomID, dist
+			}
+		}
+	}
+	if best != "" {
+		return best
+	}
+	if g.Room().Island == "Ship" {
+		return "dock"
+	}
+	return g.Player.Location
+}
+
+func (g *GameState) rivalBarred(roomID string, direction string) bool {
+	switch g.Rooms[roomID].Exits[direction] {
+	case "ruins_hall":
+		return !g.Flags["ruinUnlocked"] && len(g.Rival.Fragments) == 0
+	case "ruins_core":
+		return !g.Flags["innerUnlocked"] && len(g.Rival.Fragments) < len(glyphFragments())
+	case "navy_outpost":
+		return true
+	}
+	return false
+}
+
+func (g *GameState) roomPath(from, to string) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			break
+		}
+		exits := exitKeys(g.Rooms[current].Exits)
+		sort.Strings(exits)
+		for _, dir := range exits {
+			next := g.Rooms[current].Exits[dir]
+			if _, seen := prev[next]; seen || g.Rooms[next] == nil || g.rivalBarred(current, dir) {
+				continue
+			}
+			prev[next] = current
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := prev[to]; !ok {
+		return nil
+	}
+	path := []string{}
+	for step := to; step != from; step = prev[step] {
+		path = append([]string{step}, path...)
+	}
+	return path
+}
+
+func (g *GameState) moveRival(dest string) {
+	if room := g.Rooms[g.Rival.Location]; room != nil {
+		room.Enemies = removeID(room.Enemies, "rival_pirate")
+		if g.Rival.Location == g.Player.Location {
+			g.AddLog("Your rival tips their hat and slips away toward the "+g.Rooms[dest].Name+".", "event")
+		}
+	}
+	g.Rival.Location = dest
+	g.Rival.Standoff = 0
+	g.PlaceRival()
+	room := g.Rooms[dest]
+	if len(room.NPCs) > 0 {
+		g.Rival.LastSeen = dest
+		g.Rival.SeenAt = g.hourStamp()
+		g.Rival.SeenWith = len(g.Rival.Fragments)
+	}
+	g.MeetRival()
+}
+
+func (g *GameState) rivalScavenge() {
+	room := g.Rooms[g.Rival.Location]
+	for _, fragID := range glyphFragments() {
+		if contains(room.Items, fragID) {
+			room.Items = removeOne(room.Items, fragID)
+			g.Rival.Fragments = append(g.Rival.Fragments, fragID)
+		}
+	}
+	if g.Rival.Location == g.Player.Location {
+		return
+	}
+	for _, itemID := range room.Items {
+		item := g.Items[itemID]
+		if item.Type != "quest" && item.Value >= 15 && rand.Float64() < 0.25 {
+			room.Items = removeOne(room.Items, itemID)
+			g.Rival.Loot = append(g.Rival.Loot, itemID)
+			break
+		}
+	}
+	if contains(room.Tags, "danger") && len(room.Enemies) == 1 && rand.Float64() < 0.3 {
+		room.Enemies = append(room.Enemies, "raider")
+	}
+}
+
+func (g *GameState) RivalTick() {
+	if g.Rival.Defeated || g.Combat != nil {
+		return
+	}
+	fled := false
+	if g.Rival.Location != g.Player.Location {
+		g.Rival.Standoff = 0
+	} else if g.Rival.Standoff > 0 {
+		g.Rival.Standoff--
+		if g.Rival.Standoff > 0 {
+			return
+		}
+		g.rivalPickpocket()
+		fled = true
+	}
+	g.Rival.Clock++
+	if g.Rival.Clock%rivalPace != 0 && !fled {
+		return
+	}
+	if g.Rival.Location == "ruins_core" && len(g.Rival.Fragments) == len(glyphFragments()) {
+		g.SetFlag("treasureLost")
+		return
+	}
+	path := g.roomPath(g.Rival.Location, g.rivalTarget())
+	if len(path) == 0 {
+		for dir, next := range g.Rooms[g.Rival.Location].Exits {
+			if g.Rooms[next] != nil && g.Rooms[next].Island != "Ship" && !g.rivalBarred(g.Rival.Location, dir) {
+				path = []string{next}
+				break
+			}
+		}
+	}
+	if len(path) == 0 {
+		return
+	}
+	g.moveRival(path[0])
+	g.rivalScavenge()
+}
+
+func (g *GameState) rivalPickpocket() {
+	for _, fragID := range glyphFragments() {
+		if g.HasItem(fragID) {
+			g.removeCarried(fragID)
+			g.Rival.Fragments = append(g.Rival.Fragments, fragID)
+			g.AddLog("Your rival lunges past you and snatches the "+g.Items[fragID].Name+"!", "event")
+			return
+		}
+	}
+}
+
+func (g *GameState) MeetRival() {
+	if g.Rival.Defeated || g.Rival.Location != g.Player.Location || g.Rival.Standoff > 0 {
+		return
+	}
+	g.Rival.Standoff = 2
+	g.AddLog("Your rival is here, hand on their cutlass. Strike now or they'll make their move.", "event")
+}
+
+func (g *GameState) DefeatRival() {
+	room := g.Rooms[g.Rival.Location]
+	g.Rival.Defeated = true
+	g.Rival.Standoff = 0
+	if room == nil {
+		return
+	}
+	dropped := append(g.Rival.Fragments, g.Rival.Loot...)
+	if len(dropped) > 0 {
+		room.Items = append(room.Items, dropped...)
+		g.AddLog("Your rival drops "+g.ListItemNames(dropped)+" as they flee.", "event")
+	}
+	g.Rival.Fragments = []string{}
+	g.Rival.Loot = []string{}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed assets/rules.json
+var builtinRules []byte
+
+type Rule struct {
+	ID      string
+	On      string
+	Item    string
+	Target  string
+	Room    string
+	When    []RuleCondition
+	Effects []RuleEffect
+	Message string
+}
+
+type RuleCondition struct {
+	Flag      string
+	NotFlag   string
+	HasItem   string
+	Mood      string
+	Fruit     string
+	QuestDone string
+	MinWanted int
+	NotItem   string
+	Time      string
+	Enemy     string
+}
+
+type RuleEffect struct {
+	SetFlag  string
+	Give     string
+	Take     string
+	Stat     string
+	Island   string
+	Amount   int
+	Quest    string
+	Outcome  string
+	Helped   string
+	Befriend string
+	Log      string
+}
+
+func loadContent(name string, builtin []byte, into interface{}) error {
+	raw, err := os.ReadFile(filepath.Join("assets", name))
+	if err != nil {
+		raw = builtin
+	}
+	if err := json.Unmarshal(raw, into); err != nil {
+		json.Unmarshal(builtin, into)
+		return fmt.Errorf("%s: %v (using the built-in copy)", name, err)
+	}
+	return nil
+}
+
+func LoadRules() ([]Rule, error) {
+	var rules []Rule
+	err := loadContent("rules.json", builtinRules, &rules)
+	return rules, err
+}
+
+func (g *GameState) ruleTarget(rule Rule, target string) bool {
+	if rule.Target == "" {
+		return true
+	}
+	if rule.Target == "self" {
+		return target == "" || target == "self" || target == "me"
+	}
+	if _, isNPC := g.NPCs[rule.Target]; isNPC {
+		return g.FindNPC(target, g.Room().NPCs) == rule.Target
+	}
+	return strings.ToLower(target) == rule.Target
+}
+
+func (g *GameState) conditionsHold(when []RuleCondition, target string) bool {
+	for _, cond := range when {
+		if cond.Flag != "" && !g.Flags[cond.Flag] {
+			return false
+		}
+		if cond.NotFlag != "" && g.Flags[cond.NotFlag] {
+			return false
+		}
+		if cond.HasItem != "" && !g.HasItem(cond.HasItem) {
+			return false
+		}
+		if cond.Mood != "" && g.Mood(target) != cond.Mood {
+			return false
+		}
+		if cond.Fruit == "any" && g.Player.ActiveFruit == "" {
+			return false
+		}
+		if cond.Fruit != "" && cond.Fruit != "any" && g.Player.ActiveFruit != cond.Fruit {
+			return false
+		}
+		if cond.QuestDone != "" {
+			if quest, ok := g.Quests[cond.QuestDone]; !ok || !quest.Done {
+				return false
+			}
+		}
+		if cond.MinWanted > 0 && g.Wanted() < cond.MinWanted {
+			return false
+		}
+		if cond.NotItem != "" && g.HasItem(cond.NotItem) {
+			return false
+		}
+		if cond.Time != "" && (cond.Time == "night") != g.IsNight() {
+			return false
+		}
+		if cond.Enemy != "" && !contains(g.Room().Enemies, cond.Enemy) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *GameState) applyEffect(effect RuleEffect) {
+	if effect.SetFlag != "" {
+		g.SetFlag(effect.SetFlag)
+	}
+	if effect.Take != "" {
+		g.removeCarried(effect.Take)
+	}
+	if effect.Give != "" {
+		g.Player.Inventory = append(g.Player.Inventory, effect.Give)
+	}
+	switch effect.Stat {
+	case "morale":
+		g.Morale += effect.Amount
+	case "hp":
+		g.Player.HP = max(0, min(g.Player.MaxHP, g.Player.HP+effect.Amount))
+	case "money":
+		g.Money = max(0, g.Money+effect.Amount)
+	case "wanted":
+		g.AddWanted(effect.Amount)
+	case "hull":
+		g.RepairHull(effect.Amount)
+	case "heat":
+		g.Heat[effect.Island] = max(0, g.Heat[effect.Island]+effect.Amount)
+	}
+	if effect.Quest != "" {
+		g.CompleteQuest(effect.Quest, effect.Outcome)
+	}
+	if effect.Helped != "" {
+		g.Helped(effect.Helped)
+	}
+	if effect.Befriend != "" {
+		g.SetMood(effect.Befriend, "friendly")
+	}
+	if effect.Log != "" {
+		g.AddLog(effect.Log, "event")
+	}
+}
+
+func (g *GameState) RunRules(on string, item string, target string) (Rule, bool) {
+	for _, rule := range g.Rules {
+		if rule.On != on || (rule.Item != "" && rule.Item != item) {
+			continue
+		}
+		if rule.Room != "" && rule.Room != g.Player.Location {
+			continue
+		}
+		if !g.ruleTarget(rule, target) || !g.conditionsHold(rule.When, rule.Target) {
+			continue
+		}
+		for _, effect := range rule.Effects {
+			g.applyEffect(effect)
+		}
+		return rule, true
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+type Rumor struct {
+	Topic  string
+	Text   string
+	Source string
+	Day    int
+	True   bool
+}
+
+func (g *GameState) roomIDs() []string {
+	ids := []string{}
+	for id, room := range g.Rooms {
+		if room.Island != "Ship" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (g *GameState) randomRoom() *Room {
+	ids := g.roomIDs()
+	return g.Rooms[ids[rand.Intn(len(ids))]]
+}
+
+func (g *GameState) fragmentRumor(truthful bool) (string, bool) {
+	sites := []string{}
+	for _, roomID := range g.roomIDs() {
+		for _, fragID := range glyphFragments() {
+			if contains(g.Rooms[roomID].Items, fragID) {
+				sites = append(sites, roomID)
+			}
+		}
+	}
+	if len(sites) == 0 {
+		return "", false
+	}
+	room := g.Rooms[sites[rand.Intn(len(sites))]]
+	if !truthful {
+		room = g.randomRoom()
+	}
+	return fmt.Sprintf("An old glyph stone was seen near the %s on %s.", room.Name, room.Island), true
+}
+
+func (g *GameState) patrolRumor(truthful bool) (string, bool) {
+	islands := []string{}
+	for island, heat := range g.Heat {
+		if heat >= 3 {
+			islands = append(islands, island)
+		}
+	}
+	sort.Strings(islands)
+	if truthful && len(islands) > 0 {
+		return fmt.Sprintf("Bluecoat patrols are sweeping %s. Lie low if you're on a poster.", islands[rand.Intn(len(islands))]), true
+	}
+	for _, roomID := range g.roomIDs() {
+		if contains(g.Rooms[roomID].Tags, "checkpoint") {
+			room := g.Rooms[roomID]
+			if !truthful {
+				room = g.randomRoom()
+			}
+			return fmt.Sprintf("The Bluecoats turn out pockets at the %s. Don't carry anything you can't explain.", room.Name), true
+		}
+	}
+	return "", false
+}
+
+func (g *GameState) rivalRumor(truthful bool) (string, bool) {
+	if g.Rival.Defeated || g.Rival.LastSeen == "" {
+		return "", false
+	}
+	room := g.Rooms[g.Rival.LastSeen]
+	if !truthful {
+		room = g.randomRoom()
+	}
+	hours := g.hourStamp() - g.Rival.SeenAt
+	when := "just now"
+	if hours > 0 {
+		when = fmt.Sprintf("%d hours ago", hours)
+	}
+	return fmt.Sprintf("That flashy rival of yours was seen at the %s %s, carrying %d glyph fragments.", room.Name, when, g.Rival.SeenWith), true
+}
+
+func (g *GameState) priceRumor(truthful bool) (string, bool) {
+	bestIsland, bestItem, bestRate := "", "", 1.25
+	islands := []string{}
+	for island := range g.Market {
+		islands = append(islands, island)
+	}
+	sort.Strings(islands)
+	for _, island := range islands {
+		for itemID, rate := range g.Market[island] {
+			if rate > bestRate {
+				bestIsland, bestItem, bestRate = island, itemID, rate
+			}
+		}
+	}
+	if bestItem == "" {
+		return "", false
+	}
+	if !truthful {
+		bestIsland = islands[rand.Intn(len(islands))]
+	}
+	return fmt.Sprintf("Traders on %s are paying through the nose for %s.", bestIsland, g.Items[bestItem].Name), true
+}
+
+func (g *GameState) heardRumor(text string) bool {
+	for _, rumor := range g.Rumors {
+		if rumor.Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GameState) GenerateRumor(npcID string, falseChance float64) string {
+	sources := map[string]func(bool) (string, bool){
+		"fragments": g.fragmentRumor,
+		"patrols":   g.patrolRumor,
+		"rival":     g.rivalRumor,
+		"prices":    g.priceRumor,
+	}
+	topics := []string{"fragments", "patrols", "prices", "rival"}
+	rand.Shuffle(len(topics), func(i, j int) { topics[i], topics[j] = topics[j], topics[i] })
+	for _, topic := range topics {
+		truthful := rand.Float64() >= falseChance
+		text, ok := sources[topic](truthful)
+		if !ok || g.heardRumor(text) {
+			continue
+		}
+		g.Rumors = append(g.Rumors, Rumor{Topic: topic, Text: text, Source: npcID, Day: g.Day, True: truthful})
+		return fmt.Sprintf("The %s leans in. '%s'", g.NPCs[npcID].Name, text)
+	}
+	return fmt.Sprintf("The %s shrugs. 'Nothing you haven't heard already.'", g.NPCs[npcID].Name)
+}
+
+func (g *GameState) gossipSource(name string) (string, string) {
+	npcID := g.FindNPC(name, g.Room().NPCs)
+	if npcID == "" {
+		return "", "No one like that is here."
+	}
+	if !NPCRoutines()[npcID].Gossip {
+		return "", fmt.Sprintf("The %s doesn't trade in gossip.", g.NPCs[npcID].Name)
+	}
+	if g.Mood(npcID) == "hostile" {
+		return "", "They glare and refuse to speak."
+	}
+	return npcID, ""
+}
+
+func (g *GameState) Gossip(name string) string {
+	npcID, refusal := g.gossipSource(name)
+	if npcID == "" {
+		return g.refuse(refusal)
+	}
+	if g.Gossiped[npcID] == g.Day {
+		return g.refuse(fmt.Sprintf("The %s has said all they'll say today.", g.NPCs[npcID].Name))
+	}
+	g.Gossiped[npcID] = g.Day
+	if !g.SkillCheck("charm") {
+		return g.refuse(fmt.Sprintf("The %s changes the subject. Maybe coin or rum would help.", g.NPCs[npcID].Name))
+	}
+	return g.GenerateRumor(npcID, 0.3)
+}
+
+func (g *GameState) PayForRumor(name string) string {
+	npcID, refusal := g.gossipSource(name)
+	if npcID == "" {
+		return g.refuse(refusal)
+	}
+	cost := g.Price(10, g.NPCs[npcID].Faction)
+	if g.Money < cost {
+		return g.refuse(fmt.Sprintf("Good intel costs %d coins.", cost))
+	}
+	g.Money -= cost
+	return g.GenerateRumor(npcID, 0.2)
+}
+
+func (g *GameState) RumorForRum(name string) string {
+	npcID, refusal := g.gossipSource(name)
+	if npcID == "" {
+		return g.refuse(refusal)
+	}
+	g.Player.Inventory = removeOne(g.Player.Inventory, "rum")
+	g.ShiftAttitude(npcID, 1)
+	return g.GenerateRumor(npcID, 0.15)
+}
+
+func (g *GameState) RumorReport() string {
+	if len(g.Rumors) == 0 {
+		return "You haven't heard any rumors yet. Try a tavern."
+	}
+	lines := []string{"Rumors heard:"}
+	for i := len(g.Rumors) - 1; i >= 0; i-- {
+		rumor := g.Rumors[i]
+		lines = append(lines, fmt.Sprintf("- Day %d, %s: %s", rumor.Day, g.NPCs[rumor.Source].Name, rumor.Text))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -7,37 +7,82 @@ import (
 
 type SaveData struct {
 	Player      Player
+	Ship        Ship
 	RoomItems   map[string][]string
 	RoomEnemies map[string][]string
+	RoomNPCs    map[string][]string
+	RoomExits   map[string]map[string]string
+	Found       map[string]bool
 	Flags       map[string]bool
 	NPCState    map[string]string
+	People      map[string]*NPCRecord
+	Rival       RivalState
+	Rumors      []Rumor
+	Gossiped    map[string]int
+	Puzzles     map[string]*PuzzleState
+	Containers  map[string]*ContainerState
+	Learned     map[string]bool
+	Stats       map[string]int
+	Unlocked    map[string]bool
 	Wanted      int
+	Reputation  map[string]int
+	Heat        map[string]int
+	LastCrime   map[string]int
 	Morale      int
 	Money       int
 	Day         int
 	TimeOfDay   int
 	Discovered  map[string]bool
 	Quests      map[string]*Quest
+	Weather     string
+	Encounter   *EncounterState
+	Companions  []string
+	Stock       map[string]map[string]int
+	Market      map[string]map[string]float64
+	Haggled     map[string]float64
 }
 
 func (g *GameState) Save(filename string) string {
 	data := SaveData{
 		Player:      g.Player,
+		Ship:        g.Ship,
 		RoomItems:   map[string][]string{},
 		RoomEnemies: map[string][]string{},
+		RoomNPCs:    map[string][]string{},
+		RoomExits:   map[string]map[string]string{},
+		Found:       g.Found,
 		Flags:       g.Flags,
-		NPCState:    g.NPCState,
-		Wanted:      g.Wanted,
+		People:      g.People,
+		Rival:       g.Rival,
+		Rumors:      g.Rumors,
+		Gossiped:    g.Gossiped,
+		Puzzles:     g.Puzzles,
+		Containers:  g.Containers,
+		Learned:     g.Learned,
+		Stats:       g.Stats,
+		Unlocked:    g.Unlocked,
+		Wanted:      g.Wanted(),
+		Reputation:  g.Reputation,
+		Heat:        g.Heat,
+		LastCrime:   g.LastCrime,
 		Morale:      g.Morale,
 		Money:       g.Money,
 		Day:         g.Day,
 		TimeOfDay:   g.TimeOfDay,
 		Discovered:  g.Discovered,
 		Quests:      g.Quests,
+		Weather:     g.Weather,
+		Encounter:   g.Encounter,
+		Companions:  g.Companions,
+		Stock:       g.Stock,
+		Market:      g.Market,
+		Haggled:     g.Haggled,
 	}
 	for id, room := range g.Rooms {
 		data.RoomItems[id] = append([]string{}, room.Items...)
 		data.RoomEnemies[id] = append([]string{}, room.Enemies...)
+		data.RoomNPCs[id] = append([]string{}, room.NPCs...)
+		data.RoomExits[id] = room.Exits
 	}
 	raw, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
@@ -59,26 +104,103 @@ func (g *GameState) Load(filename string) string {
 		return "Save file corrupted."
 	}
 	fresh := NewGameState()
+	fresh.Events = g.Events
 	*g = *fresh
 	g.Player = data.Player
+	if g.Player.Macros == nil {
+		g.Player.Macros = map[string]string{}
+	}
+	if g.Player.Status == nil {
+		g.Player.Status = map[string]int{}
+	}
+	if g.Player.Fuel == nil {
+		g.Player.Fuel = map[string]int{}
+	}
+	if data.Ship.MaxHull > 0 {
+		g.Ship = data.Ship
+	}
 	g.Flags = data.Flags
-	g.NPCState = data.NPCState
-	g.Wanted = data.Wanted
+	if data.Rival.Location != "" {
+		g.Rival = data.Rival
+	}
+	if data.Rumors != nil {
+		g.Rumors = data.Rumors
+		g.Gossiped = data.Gossiped
+	}
+	if data.Puzzles != nil {
+		g.Puzzles = data.Puzzles
+	}
+	if data.Containers != nil {
+		g.Containers = data.Containers
+	}
+	if data.Learned != nil {
+		g.Learned = data.Learned
+	}
+	if data.Found != nil {
+		g.Found = data.Found
+	}
+	if data.Stats != nil {
+		g.Stats = data.Stats
+		g.Unlocked = data.Unlocked
+	}
+	if data.People != nil {
+		g.People = data.People
+	}
+	for id, mood := range data.NPCState {
+		g.SetMood(id, mood)
+		g.person(id).Met = mood != "neutral"
+	}
+	if data.Reputation != nil {
+		g.Reputation = data.Reputation
+	}
+	if data.Heat != nil {
+		g.Heat = data.Heat
+		g.LastCrime = data.LastCrime
+	} else {
+		g.Heat["Harbor Isle"] = data.Wanted
+	}
 	g.Morale = data.Morale
 	g.Money = data.Money
 	g.Day = data.Day
 	g.TimeOfDay = data.TimeOfDay
 	g.Discovered = data.Discovered
 	g.Quests = data.Quests
+	if data.Weather != "" {
+		g.Weather = data.Weather
+	}
+	g.Encounter = data.Encounter
 	for id, items := range data.RoomItems {
 		if room, ok := g.Rooms[id]; ok {
 			room.Items = items
 		}
 	}
+	if data.Companions != nil {
+		g.Companions = data.Companions
+	}
+	if data.Stock != nil {
+		g.Stock = data.Stock
+	}
+	if data.Market != nil {
+		g.Market = data.Market
+	}
+	if data.Haggled != nil {
+		g.Haggled = data.Haggled
+	}
+	for id, npcs := range data.RoomNPCs {
+		if room, ok := g.Rooms[id]; ok {
+			room.NPCs = npcs
+		}
+	}
+	for id, exits := range data.RoomExits {
+		if room, ok := g.Rooms[id]; ok && exits != nil {
+			room.Exits = exits
+		}
+	}
 	for id, enemies := range data.RoomEnemies {
 		if room, ok := g.Rooms[id]; ok {
 			room.Enemies = enemies
 		}
 	}
+	g.ResolveQuests()
 	return "Game loaded."
 }
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const scriptBudget = 5000
+
+type scriptToken struct {
+	Kind string
+	Text string
+	Line int
+}
+
+type scriptNode struct {
+	Kind     string
+	Text     string
+	Value    interface{}
+	Children []*scriptNode
+	Line     int
+}
+
+type Script struct {
+	Name      string
+	Functions map[string]*scriptNode
+}
+
+type scriptFunc func(args []interface{}) (interface{}, error)
+
+type scriptReturn struct {
+	Value interface{}
+}
+
+type scriptVM struct {
+	Globals map[string]interface{}
+	API     map[string]scriptFunc
+	Steps   int
+	Scopes  []map[string]interface{}
+}
+
+func lexScript(source string) ([]scriptToken, error) {
+	tokens := []scriptToken{}
+	line := 1
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case ch == '"' || ch == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != ch && runes[end] != '\n' {
+				end++
+			}
+			if end >= len(runes) || runes[end] != ch {
+				return nil, fmt.Errorf("line %d: unfinished string", line)
+			}
+			tokens = append(tokens, scriptToken{Kind: "string", Text: string(runes[i+1 : end]), Line: line})
+			i = end + 1
+		case unicode.IsDigit(ch):
+			end := i
+			for end < len(runes) && unicode.IsDigit(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, scriptToken{Kind: "number", Text: string(runes[i:end]), Line: line})
+			i = end
+		case unicode.IsLetter(ch) || ch == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, scriptToken{Kind: "name", Text: string(runes[i:end]), Line: line})
+			i = end
+		default:
+			text := string(ch)
+			if i+1 < len(runes) {
+				pair := string(runes[i : i+2])
+				switch pair {
+				case "==", "~=", "<=", ">=", "..":
+					text = pair
+				}
+			}
+			if !strings.Contains("+-*/()<>,=", text) && len(text) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected %q", line, text)
+			}
+			tokens = append(tokens, scriptToken{Kind: "op", Text: text, Line: line})
+			i += len([]rune(text))
+		}
+	}
+	return append(tokens, scriptToken{Kind: "eof", Line: line}), nil
+}
+
+type scriptParser struct {
+	tokens []scriptToken
+	pos    int
+}
+
+func (p *scriptParser) peek() scriptToken {
+	return p.tokens[p.pos]
+}
+
+func (p *scriptParser) next() scriptToken {
+	token := p.tokens[p.pos]
+	if token.Kind != "eof" {
+		p.pos++
+	}
+	return token
+}
+
+func (p *scriptParser) is(text string) bool {
+	token := p.peek()
+	return (token.Kind == "op" || token.Kind == "name") && token.Text == text
+}
+
+func (p *scriptParser) expect(text string) error {
+	if !p.is(text) {
+		token := p.peek()
+		return fmt.Errorf("line %d: expected %q, found %q", token.Line, text, token.Text)
+	}
+	p.next()
+	return nil
+}
+
+func ParseScript(name string, source string) (*Script, error) {
+	tokens, err := lexScript(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &scriptParser{tokens: tokens}
+	script := &Script{Name: name, Functions: map[string]*scriptNode{}}
+	for p.peek().Kind != "eof" {
+		if err := p.expect("function"); err != nil {
+			return nil, err
+		}
+		fn := p.next()
+		if fn.Kind != "name" {
+			return nil, fmt.Errorf("line %d: expected function name", fn.Line)
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		body, err := p.block("end")
+		if err != nil {
+			return nil, err
+		}
+		p.next()
+		script.Functions[fn.Text] = body
+	}
+	return script, nil
+}
+
+func (p *scriptParser) block(terminators ...string) (*scriptNode, error) {
+	block := &scriptNode{Kind: "block", Line: p.peek().Line}
+	for {
+		token := p.peek()
+		if token.Kind == "eof" {
+			return nil, fmt.Errorf("line %d: missing %q", token.Line, terminators[0])
+		}
+		for _, term := range terminators {
+			if p.is(term) {
+				return block, nil
+			}
+		}
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		block.Children = append(block.Children, stmt)
+	}
+}
+
+func (p *scriptParser) statement() (*scriptNode, error) {
+	token := p.peek()
+	switch {
+	case p.is("if"):
+		return p.ifStatement()
+	case p.is("return"):
+		p.next()
+		node := &scriptNode{Kind: "return", Line: token.Line}
+		if !p.is("end") && !p.is("else") && !p.is("elseif") && p.peek().Kind != "eof" {
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			node.Children = []*scriptNode{value}
+		}
+		return node, nil
+	case p.is("local"):
+		p.next()
+		name := p.next()
+		if name.Kind != "name" {
+			return nil, fmt.Errorf("line %d: expected a name after local", name.Line)
+		}
+		node := &scriptNode{Kind: "local", Text: name.Text, Line: token.Line}
+		if p.is("=") {
+			p.next()
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			node.Children = []*scriptNode{value}
+		}
+		return node, nil
+	case token.Kind == "name" && p.tokens[p.pos+1].Text == "=" && p.tokens[p.pos+1].Kind == "op":
+		p.next()
+		p.next()
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return &scriptNode{Kind: "assign", Text: token.Text, Children: []*scriptNode{value}, Line: token.Line}, nil
+	}
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if expr.Kind != "call" {
+		return nil, fmt.Errorf("line %d: expected a statement", token.Line)
+	}
+	return expr, nil
+}
+
+func (p *scriptParser) ifStatement() (*scriptNode, error) {
+	node := &scriptNode{Kind: "if", Line: p.peek().Line}
+	for p.is("if") || p.is("elseif") {
+		p.next()
+		cond, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		body, err := p.block("elseif", "else", "end")
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, cond, body)
+	}
+	if p.is("else") {
+		p.next()
+		body, err := p.block("end")
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, &scriptNode{Kind: "literal", Value: true}, body)
+	}
+	return node, p.expect("end")
+}
+
+var scriptPrecedence = []map[string]bool{
+	{"or": true},
+	{"and": true},
+	{"==": true, "~=": true, "<": true, "<=": true, ">": true, ">=": true},
+	{"..": true},
+	{"+": true, "-": true},
+	{"*": true, "/": true},
+}
+
+func (p *scriptParser) expression() (*scriptNode, error) {
+	return p.binary(0)
+}
+
+func (p *scriptParser) binary(level int) (*scriptNode, error) {
+	if level == len(scriptPrecedence) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		token := p.peek()
+		if (token.Kind != "op" && token.Kind != "name") || !scriptPrecedence[level][token.Text] {
+			return left, nil
+		}
+		p.next()
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &scriptNode{Kind: "binary", Text: token.Text, Children: []*scriptNode{left, right}, Line: token.Line}
+	}
+}
+
+func (p *scriptParser) unary() (*scriptNode, error) {
+	token := p.peek()
+	if p.is("not") || p.is("-") {
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &scriptNode{Kind: "unary", Text: token.Text, Children: []*scriptNode{operand}, Line: token.Line}, nil
+	}
+	return p.primary()
+}
+
+func (p *scriptParser) primary() (*scriptNode, error) {
+	token := p.next()
+	switch token.Kind {
+	case "number":
+		value, _ := strconv.Atoi(token.Text)
+		return &scriptNode{Kind: "literal", Value: value, Line: token.Line}, nil
+	case "string":
+		return &scriptNode{Kind: "literal", Value: token.Text, Line: token.Line}, nil
+	case "name":
+		switch token.Text {
+		case "true":
+			return &scriptNode{Kind: "literal", Value: true, Line: token.Line}, nil
+		case "false":
+			return &scriptNode{Kind: "literal", Value: false, Line: token.Line}, nil
+		case "nil":
+			return &scriptNode{Kind: "literal", Value: nil, Line: token.Line}, nil
+		}
+		if !p.is("(") {
+			return &scriptNode{Kind: "name", Text: token.Text, Line: token.Line}, nil
+		}
+		p.next()
+		call := &scriptNode{Kind: "call", Text: token.Text, Line: token.Line}
+		for !p.is(")") {
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			call.Children = append(call.Children, arg)
+			if !p.is(",") {
+				break
+			}
+			p.next()
+		}
+		return call, p.expect(")")
+	case "op":
+		if token.Text == "(" {
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("line %d: unexpected %q", token.Line, token.Text)
+}
+
+func (vm *scriptVM) Call(script *Script, fn string) (interface{}, error) {
+	body, ok := script.Functions[fn]
+	if !ok {
+		return nil, nil
+	}
+	vm.Steps = 0
+	vm.Scopes = nil
+	err := vm.exec(body)
+	if ret, ok := err.(scriptReturn); ok {
+		return ret.Value, nil
+	}
+	return nil, err
+}
+
+func (r scriptReturn) Error() string {
+	return "return"
+}
+
+func (vm *scriptVM) exec(node *scriptNode) error {
+	vm.Steps++
+	if vm.Steps > scriptBudget {
+		return fmt.Errorf("script ran too long")
+	}
+	switch node.Kind {
+	case "block":
+		vm.Scopes = append(vm.Scopes, map[string]interface{}{})
+		defer func() { vm.Scopes = vm.Scopes[:len(vm.Scopes)-1] }()
+		for _, stmt := range node.Children {
+			if err := vm.exec(stmt); err != nil {
+				return err
+			}
+		}
+	case "if":
+		for i := 0; i < len(node.Children); i += 2 {
+			cond, err := vm.eval(node.Children[i])
+			if err != nil {
+				return err
+			}
+			if truthy(cond) {
+				return vm.exec(node.Children[i+1])
+			}
+		}
+	case "return":
+		var value interface{}
+		if len(node.Children) > 0 {
+			var err error
+			if value, err = vm.eval(node.Children[0]); err != nil {
+				return err
+			}
+		}
+		return scriptReturn{Value: value}
+	case "local":
+		var value interface{}
+		if len(node.Children) > 0 {
+			var err error
+			if value, err = vm.eval(node.Children[0]); err != nil {
+				return err
+			}
+		}
+		vm.Scopes[len(vm.Scopes)-1][node.Text] = value
+	case "assign":
+		value, err := vm.eval(node.Children[0])
+		if err != nil {
+			return err
+		}
+		if scope := vm.scopeOf(node.Text); scope != nil {
+			scope[node.Text] = value
+		} else {
+			vm.Globals[node.Text] = value
+		}
+	default:
+		_, err := vm.eval(node)
+		return err
+	}
+	return nil
+}
+
+func (vm *scriptVM) scopeOf(name string) map[string]interface{} {
+	for i := len(vm.Scopes) - 1; i >= 0; i-- {
+		if _, ok := vm.Scopes[i][name]; ok {
+			return vm.Scopes[i]
+		}
+	}
+	return nil
+}
+
+func truthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return true
+}
+
+func (vm *scriptVM) eval(node *scriptNode) (interface{}, error) {
+	vm.Steps++
+	if vm.Steps > scriptBudget {
+		return nil, fmt.Errorf("script ran too long")
+	}
+	switch node.Kind {
+	case "literal":
+		return node.Value, nil
+	case "name":
+		if scope := vm.scopeOf(node.Text); scope != nil {
+			return scope[node.Text], nil
+		}
+		return vm.Globals[node.Text], nil
+	case "call":
+		fn, ok := vm.API[node.Text]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown function %s", node.Line, node.Text)
+		}
+		args := []interface{}{}
+		for _, child := range node.Children {
+			value, err := vm.eval(child)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, value)
+		}
+		value, err := fn(args)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %v", node.Line, node.Text, err)
+		}
+		return value, nil
+	case "unary":
+		value, err := vm.eval(node.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		if node.Text == "not" {
+			return !truthy(value), nil
+		}
+		n, ok := value.(int)
+		if !ok {
+			return nil, fmt.Errorf("line %d: cannot negate %v", node.Line, value)
+		}
+		return -n, nil
+	case "binary":
+		return vm.binary(node)
+	}
+	return nil, fmt.Errorf("line %d: cannot evaluate %s", node.Line, node.Kind)
+}
+
+func (vm *scriptVM) binary(node *scriptNode) (interface{}, error) {
+	left, err := vm.eval(node.Children[0])
+	if err != nil {
+		return nil, err
+	}
+	switch node.Text {
+	case "and":
+		if !truthy(left) {
+			return left, nil
+		}
+		return vm.eval(node.Children[1])
+	case "or":
+		if truthy(left) {
+			return left, nil
+		}
+		return vm.eval(node.Children[1])
+	}
+	right, err := vm.eval(node.Children[1])
+	if err != nil {
+		return nil, err
+	}
+	switch node.Text {
+	case "==":
+		return left == right, nil
+	case "~=":
+		return left != right, nil
+	case "..":
+		return fmt.Sprint(scriptString(left), scriptString(right)), nil
+	}
+	a, okA := left.(int)
+	b, okB := right.(int)
+	if !okA || !okB {
+		return nil, fmt.Errorf("line %d: %s needs numbers", node.Line, node.Text)
+	}
+	switch node.Text {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("line %d: division by zero", node.Line)
+		}
+		return a / b, nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	}
+	return nil, fmt.Errorf("line %d: unknown operator %s", node.Line, node.Text)
+}
+
+func scriptString(value interface{}) string {
+	if value == nil {
+		return "nil"
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func runScript(t *testing.T, source string, fn string) (interface{}, error) {
+	t.Helper()
+	script, err := ParseScript("test", source)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	vm := &scriptVM{Globals: map[string]interface{}{}, API: map[string]scriptFunc{}}
+	return vm.Call(script, fn)
+}
+
+func TestLexErrors(t *testing.T) {
+	cases := []struct {
+		source string
+		want   string
+	}{
+		{"function f()\n  return \"open\nend", "line 2: unfinished string"},
+		{"function f()\n  if 1 != 2 then end\nend", "line 2: unexpected \"!\""},
+		{"function f()\n  x = 1 # 2\nend", "line 2: unexpected \"#\""},
+	}
+	for _, c := range cases {
+		_, err := ParseScript("test", c.source)
+		if err == nil || err.Error() != c.want {
+			t.Errorf("ParseScript(%q) error = %v, want %q", c.source, err, c.want)
+		}
+	}
+}
+
+func TestIfElseifElse(t *testing.T) {
+	source := `
+function pick()
+  if n < 0 then
+    return "negative"
+  elseif n == 0 then
+    return "zero"
+  elseif n < 10 then
+    return "small"
+  else
+    return "large"
+  end
+end`
+	script, err := ParseScript("test", source)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for n, want := range map[int]string{-3: "negative", 0: "zero", 4: "small", 99: "large"} {
+		vm := &scriptVM{Globals: map[string]interface{}{"n": n}, API: map[string]scriptFunc{}}
+		got, err := vm.Call(script, "pick")
+		if err != nil || got != want {
+			t.Errorf("pick with n=%d = %v, %v; want %q", n, got, err, want)
+		}
+	}
+}
+
+func TestReturn(t *testing.T) {
+	got, err := runScript(t, `
+function f()
+  x = 1
+  if true then
+    return x + 1
+  end
+  x = 99
+  return x
+end`, "f")
+	if err != nil || got != 2 {
+		t.Errorf("early return = %v, %v; want 2", got, err)
+	}
+	got, err = runScript(t, "function f()\n  return\nend", "f")
+	if err != nil || got != nil {
+		t.Errorf("bare return = %v, %v; want nil", got, err)
+	}
+	got, err = runScript(t, "function f()\nend", "missing")
+	if err != nil || got != nil {
+		t.Errorf("missing function = %v, %v; want nil", got, err)
+	}
+}
+
+func TestLocalScope(t *testing.T) {
+	source := `
+function f()
+  x = "global"
+  local y = "outer"
+  if true then
+    local x = "inner"
+    y = x
+  end
+  return x .. " " .. y
+end`
+	script, err := ParseScript("test", source)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	vm := &scriptVM{Globals: map[string]interface{}{}, API: map[string]scriptFunc{}}
+	got, err := vm.Call(script, "f")
+	if err != nil || got != "global inner" {
+		t.Errorf("f() = %v, %v; want %q", got, err, "global inner")
+	}
+	if _, leaked := vm.Globals["y"]; leaked {
+		t.Errorf("local y leaked into globals")
+	}
+	if _, err := ParseScript("test", "function f()\n  local 3 = 1\nend"); err == nil {
+		t.Errorf("local without a name parsed")
+	}
+}
+
+func TestStepBudget(t *testing.T) {
+	source := "function f()\n  return 1" + strings.Repeat(" + 1", scriptBudget) + "\nend"
+	_, err := runScript(t, source, "f")
+	if err == nil || !strings.Contains(err.Error(), "ran too long") {
+		t.Errorf("long script error = %v, want budget error", err)
+	}
+	got, err := runScript(t, "function f()\n  return 1 + 1 + 1\nend", "f")
+	if err != nil || got != 3 {
+		t.Errorf("short script = %v, %v; want 3", got, err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Secret struct {
+	ID      string
+	Room    string
+	Items   []string
+	Exit    string
+	To      string
+	Money   int
+	Tools   []string
+	Check   bool
+	Message string
+}
+
+func Secrets() []Secret {
+	return []Secret{
+		{ID: "spring_path", Room: "jungle_grove", Exit: "west", To: "sunken_spring", Tools: []string{"compass"}, Message: "The compass needle swings hard west, toward a gap in the vines you'd swear wasn't there."},
+		{ID: "beach_cache", Room: "ember_beach", Items: []string{"buried_cache"}, Tools: []string{"map_scrap", "marked_chart"}, Message: "You pace it out from the scrap's markings and dig. Your hands hit iron-banded wood."},
+		{ID: "tavern_purse", Room: "tavern", Money: 15, Check: true, Message: "Under a sticky table you find a dropped purse. Finders keepers."},
+		{ID: "cabin_plank", Room: "ship_cabin", Items: []string{"repair_kit"}, Check: true, Message: "A loose plank under the bunk hides a forgotten repair kit."},
+		{ID: "reef_pearl", Room: "reef_shallows", Items: []string{"pearl"}, Check: true, Message: "Something glints between the coral. You fish out a pearl."},
+	}
+}
+
+func (g *GameState) OpenExit(roomID string, direction string, dest string) {
+	if room, ok := g.Rooms[roomID]; ok {
+		room.Exits[direction] = dest
+	}
+}
+
+func (g *GameState) CloseExit(roomID string, direction string) {
+	if room, ok := g.Rooms[roomID]; ok {
+		delete(room.Exits, direction)
+	}
+}
+
+func (g *GameState) hasAnyItem(ids []string) bool {
+	for _, id := range ids {
+		if g.HasItem(id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *GameState) reveal(secret Secret) string {
+	g.Found[secret.ID] = true
+	room := g.Rooms[secret.Room]
+	room.Items = append(room.Items, secret.Items...)
+	if secret.Exit != "" {
+		g.OpenExit(secret.Room, secret.Exit, secret.To)
+	}
+	g.Money += secret.Money
+	g.Emit(SecretFound, secret.ID, secret.Room)
+	return secret.Message
+}
+
+func (g *GameState) Search() string {
+	room := g.Room()
+	if len(room.Enemies) > 0 {
+		return g.refuse(fmt.Sprintf("Not with the %s breathing down your neck.", g.Enemies[room.Enemies[0]].Name))
+	}
+	if g.InDarkness() {
+		return g.refuse("You can't search what you can't see.")
+	}
+	g.AdvanceTime()
+	lines := []string{}
+	rolled, checked := false, false
+	for _, secret := range Secrets() {
+		if secret.Room != room.ID || g.Found[secret.ID] {
+			continue
+		}
+		if g.hasAnyItem(secret.Tools) {
+			lines = append(lines, g.reveal(secret))
+			continue
+		}
+		if !secret.Check {
+			continue
+		}
+		if !rolled {
+			rolled, checked = true, g.SkillCheck("wits")
+		}
+		if checked {
+			lines = append(lines, g.reveal(secret))
+		}
+	}
+	if len(lines) == 0 {
+		return "You search high and low and turn up nothing."
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Ship struct {
+	Hull         int
+	MaxHull      int
+	HoldSlots    int
+	Hold         []string
+	Crew         int
+	Food         int
+	MaxFood      int
+	Rum          int
+	MaxRum       int
+	UpgradeSlots int
+	Upgrades     []string
+	HiddenSlots  int
+	Hidden       []string
+}
+
+type ShipUpgrade struct {
+	ID          string
+	HullBonus   int
+	RiskMod     int
+	HoursMod    int
+	HiddenSlots int
+}
+
+func NewShip() Ship {
+	return Ship{Hull: 30, MaxHull: 30, HoldSlots: 10, Hold: []string{}, Crew: 4, Food: 8, MaxFood: 12, Rum: 4, MaxRum: 8, UpgradeSlots: 2, Upgrades: []string{}, Hidden: []string{}}
+}
+
+func ShipUpgrades() map[string]ShipUpgrade {
+	return map[string]ShipUpgrade{
+		"hull_plates":   {ID: "hull_plates", HullBonus: 15, RiskMod: -1},
+		"swift_sails":   {ID: "swift_sails", HoursMod: -1, RiskMod: -1},
+		"smuggler_hold": {ID: "smuggler_hold", HiddenSlots: 3},
+	}
+}
+
+func (s *Ship) HoldUsed(items map[string]*Item) int {
+	count := 0
+	for _, itemID := range s.Hold {
+		count += items[itemID].Slots
+	}
+	return count
+}
+
+func (s *Ship) RiskMod() int {
+	mod := 0
+	upgrades := ShipUpgrades()
+	for _, id := range s.Upgrades {
+		mod += upgrades[id].RiskMod
+	}
+	if s.Hull*3 < s.MaxHull {
+		mod++
+	}
+	return mod
+}
+
+func (s *Ship) VoyageHours(risk int) int {
+	hours := risk
+	upgrades := ShipUpgrades()
+	for _, id := range s.Upgrades {
+		hours += upgrades[id].HoursMod
+	}
+	return max(0, hours)
+}
+
+func (g *GameState) ConsumeSupplies() {
+	ship := &g.Ship
+	food := 1 + ship.Crew/4
+	if ship.Food >= food {
+		ship.Food -= food
+		g.Player.Hunger = 0
+	} else {
+		ship.Food = 0
+		g.Morale--
+		g.AddLog("The galley is bare. The crew grumbles on empty stomachs.", "event")
+		g.CrewComment("hungry")
+	}
+	if ship.Rum > 0 {
+		ship.Rum--
+	} else {
+		g.Morale--
+		g.AddLog("No rum ration today. Morale sinks.", "event")
+	}
+}
+
+func (g *GameState) DamageHull(amount int) {
+	g.Ship.Hull = max(0, g.Ship.Hull-amount)
+	if g.Ship.Hull > 0 && g.Ship.Hull*3 < g.Ship.MaxHull {
+		g.AddLog("The hull groans. She won't take much more.", "event")
+	}
+}
+
+func (g *GameState) RepairHull(amount int) int {
+	before := g.Ship.Hull
+	g.Ship.Hull = min(g.Ship.MaxHull, g.Ship.Hull+amount)
+	return g.Ship.Hull - before
+}
+
+func (g *GameState) Repair() string {
+	room := g.Room()
+	if g.FindNPC("shipwright", room.NPCs) == "" {
+		return g.refuse("You need a shipwright for proper repairs.")
+	}
+	missing := g.Ship.MaxHull - g.Ship.Hull
+	if missing == 0 {
+		return "The shipwright knocks on your hull. 'Sound as a drum.'"
+	}
+	cost := g.Price(missing*2, "")
+	for missing > 0 && g.Money < cost {
+		missing--
+		cost = g.Price(missing*2, "")
+	}
+	if missing == 0 {
+		return g.refuse("You can't afford any repairs.")
+	}
+	g.Money -= cost
+	g.RepairHull(missing)
+	return fmt.Sprintf("The shipwright patches %d hull for %d coins.", missing, cost)
+}
+
+func (g *GameState) Provision() string {
+	room := g.Room()
+	if !contains(room.Tags, "dock") || room.Island == "Ship" {
+		return g.refuse("You can only take on supplies at a dock.")
+	}
+	food := g.Ship.MaxFood - g.Ship.Food
+	rum := g.Ship.MaxRum - g.Ship.Rum
+	if food == 0 && rum == 0 {
+		return "The galley and rum locker are already full."
+	}
+	cost := g.Price(food*2+rum*3, "")
+	if g.Money < cost {
+		return g.refuse(fmt.Sprintf("Topping up costs %d coins. You don't have enough.", cost))
+	}
+	g.Money -= cost
+	g.Ship.Food += food
+	g.Ship.Rum += rum
+	return fmt.Sprintf("Dockhands roll aboard %d food and %d rum for %d coins.", food, rum, cost)
+}
+
+func (g *GameState) Stow(name string) string {
+	if g.Room().Island != "Ship" {
+		return g.refuse("You need to be aboard to reach the hold.")
+	}
+	itemID := g.FindItem(name, g.Player.Inventory)
+	if itemID == "" {
+		return g.refuse("You don't have that.")
+	}
+	if refusal := g.notEmpty(itemID); refusal != "" {
+		return g.refuse(refusal)
+	}
+	item := g.Items[itemID]
+	if g.Ship.HoldUsed(g.Items)+item.Slots > g.Ship.HoldSlots {
+		return g.refuse("The hold is packed to the beams.")
+	}
+	g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+	g.Ship.Hold = append(g.Ship.Hold, itemID)
+	return fmt.Sprintf("You stow the %s in the hold.", item.Name)
+}
+
+func (g *GameState) Unstow(name string) string {
+	if g.Room().Island != "Ship" {
+		return g.refuse("You need to be aboard to reach the hold.")
+	}
+	itemID := g.FindItem(name, g.Ship.Hold)
+	if itemID == "" {
+		return g.refuse("That isn't in the hold.")
+	}
+	item := g.Items[itemID]
+	if g.InventorySlots()+g.slotsFor(itemID) > g.Player.MaxSlots {
+		return g.refuse("You're carrying too much already.")
+	}
+	g.Ship.Hold = removeOne(g.Ship.Hold, itemID)
+	g.Player.Inventory = append(g.Player.Inventory, itemID)
+	return fmt.Sprintf("You haul the %s out of the hold.", item.Name)
+}
+
+func (g *GameState) InstallUpgrade(itemID string) string {
+	upgrade, ok := ShipUpgrades()[itemID]
+	if !ok {
+		return g.refuse("That doesn't fit a ship.")
+	}
+	room := g.Room()
+	if room.Island != "Ship" && g.FindNPC("shipwright", room.NPCs) == "" {
+		return g.refuse("Upgrades need to be fitted aboard or at the shipyard.")
+	}
+	if contains(g.Ship.Upgrades, itemID) {
+		return g.refuse("Your ship already has that fitted.")
+	}
+	if len(g.Ship.Upgrades) >= g.Ship.UpgradeSlots {
+		return g.refuse("There's no room left to fit another upgrade.")
+	}
+	g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+	g.Ship.Upgrades = append(g.Ship.Upgrades, itemID)
+	g.Ship.MaxHull += upgrade.HullBonus
+	g.Ship.Hull += upgrade.HullBonus
+	g.Ship.HiddenSlots += upgrade.HiddenSlots
+	return fmt.Sprintf("You fit the %s. Your ship feels keener already.", g.Items[itemID].Name)
+}
+
+func (g *GameState) ShipReport() string {
+	ship := g.Ship
+	lines := []string{
+		fmt.Sprintf("Hull %d/%d  Crew %d", ship.Hull, ship.MaxHull, ship.Crew),
+		fmt.Sprintf("Food %d/%d  Rum %d/%d", ship.Food, ship.MaxFood, ship.Rum, ship.MaxRum),
+		fmt.Sprintf("Hold %d/%d", ship.HoldUsed(g.Items), ship.HoldSlots),
+	}
+	if len(ship.Hold) > 0 {
+		lines = append(lines, "Cargo: "+g.ListItemNames(ship.Hold))
+	}
+	if hidden := g.HiddenReport(); hidden != "" {
+		lines = append(lines, hidden)
+	}
+	if len(g.Companions) > 0 {
+		lines = append(lines, "Companions: "+g.ListNPCNames(g.Companions))
+	}
+	upgrades := "none"
+	if len(ship.Upgrades) > 0 {
+		upgrades = g.ListItemNames(ship.Upgrades)
+	}
+	lines = append(lines, fmt.Sprintf("Upgrades (%d/%d): %s", len(ship.Upgrades), ship.UpgradeSlots, upgrades))
+	return strings.Join(lines, "\n")
+}
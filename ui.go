@@ -47,6 +47,12 @@ type UIState struct {
 	MapPath      []string
 	ConfirmMove  bool
 	Focus        string
+	Toasts       []Toast
+}
+
+type Toast struct {
+	Text   string
+	Frames int
 }
 
 type ModalState struct {
@@ -150,6 +156,24 @@ func (ui *UIState) UpdateInput() {
 	ui.MouseDown = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
 }
 
+func (ui *UIState) Toast(text string) {
+	ui.Toasts = append(ui.Toasts, Toast{Text: text, Frames: 240})
+	if len(ui.Toasts) > 3 {
+		ui.Toasts = ui.Toasts[len(ui.Toasts)-3:]
+	}
+}
+
+func (ui *UIState) TickToasts() {
+	active := ui.Toasts[:0]
+	for _, toast := range ui.Toasts {
+		toast.Frames--
+		if toast.Frames > 0 {
+			active = append(active, toast)
+		}
+	}
+	ui.Toasts = active
+}
+
 // DrawSimplePanel draws a text-only panel: white outline, optional title, no fill. Returns content rect.
 func (r *Renderer) DrawSimplePanel(screen *ebiten.Image, rect Rect, title string) Rect {
 	strokeRoundedRect(screen, rect, r.Tokens.Radius["md"], r.Tokens.Colors["text"])
@@ -351,6 +375,19 @@ func (r *Renderer) DrawTooltip(screen *ebiten.Image, tooltip *TooltipState) {
 	text.Draw(screen, tooltip.Body, r.Small, int(rect.X+pad), int(rect.Y+pad+24), r.Tokens.Colors["textMuted"])
 }
 
+func (r *Renderer) DrawToasts(screen *ebiten.Image, toasts []Toast) {
+	pad := r.Tokens.Spacing["sm"]
+	y := pad
+	for _, toast := range toasts {
+		w := float64(textWidth(toast.Text, r.Small)) + pad*2
+		rect := Rect{X: float64(screen.Bounds().Dx()) - w - pad, Y: y, W: w, H: 28}
+		drawRoundedRect(screen, rect, r.Tokens.Radius["sm"], r.Tokens.Colors["surface2"])
+		strokeRoundedRect(screen, rect, r.Tokens.Radius["sm"], r.Tokens.Colors["success"])
+		text.Draw(screen, toast.Text, r.Small, int(rect.X+pad), int(rect.Y+19), r.Tokens.Colors["text"])
+		y += rect.H + pad/2
+	}
+}
+
 func (r *Renderer) DrawModal(screen *ebiten.Image, modal *ModalState, state UIState) string {
 	if modal == nil {
 		return ""
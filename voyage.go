@@ -0,0 +1,265 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+//go:embed assets/sea_events.json
+var builtinSeaEvents []byte
+
+type SeaEvent struct {
+	ID        string
+	Name      string
+	Text      string
+	Weight    float64
+	MinRisk   int
+	MinWanted int
+	Weather   []string
+	Enemy     string
+	Check     string
+	Success   SeaOutcome
+	Failure   SeaOutcome
+	Choices   []SeaChoice
+}
+
+type SeaChoice struct {
+	Label   string
+	Cost    int
+	Check   string
+	Success SeaOutcome
+	Failure SeaOutcome
+}
+
+type SeaOutcome struct {
+	Text   string
+	Money  int
+	Wanted int
+	Morale int
+	HP     int
+	Hull   int
+	Hours  int
+	Item   string
+	Enemy  string
+	Search bool
+	Rep    map[string]int
+}
+
+type EncounterState struct {
+	EventID string
+	Title   string
+	Body    string
+	Choices []string
+}
+
+func LoadSeaEvents() ([]SeaEvent, error) {
+	var events []SeaEvent
+	err := loadContent("sea_events.json", builtinSeaEvents, &events)
+	return events, err
+}
+
+func riskLevel(risk string) int {
+	switch risk {
+	case "Low":
+		return 1
+	case "Medium":
+		return 2
+	case "High":
+		return 3
+	case "Severe":
+		return 4
+	default:
+		return 0
+	}
+}
+
+func findRoute(from, to string) (WorldRoute, bool) {
+	for _, route := range BuildWorldMap().Routes {
+		if (route.From == from && route.To == to) || (route.From == to && route.To == from) {
+			return route, true
+		}
+	}
+	return WorldRoute{}, false
+}
+
+func (g *GameState) RollWeather() {
+	roll := rand.Float64()
+	switch {
+	case roll < 0.5:
+		g.Weather = "clear"
+	case roll < 0.7:
+		g.Weather = "fog"
+	case roll < 0.9:
+		g.Weather = "squall"
+	default:
+		g.Weather = "storm"
+	}
+}
+
+func (g *GameState) Voyage(from, to string) {
+	route, ok := findRoute(from, to)
+	if !ok {
+		return
+	}
+	for i := 0; i < g.Ship.VoyageHours(riskLevel(route.Risk)); i++ {
+		g.AdvanceTime()
+	}
+	g.ConsumeSupplies()
+	g.CrewComment("voyage")
+	risk := max(1, riskLevel(route.Risk)+g.Ship.RiskMod()+g.CrewRiskMod())
+	chance := 0.1 + float64(risk)*0.1 + float64(g.Wanted())*0.05
+	switch g.Weather {
+	case "fog":
+		chance += 0.05
+	case "squall":
+		chance += 0.1
+	case "storm":
+		chance += 0.2
+	}
+	if rand.Float64() >= chance {
+		return
+	}
+	event, ok := g.pickSeaEvent(risk)
+	if !ok {
+		return
+	}
+	g.StartSeaEvent(event)
+}
+
+func (g *GameState) pickSeaEvent(risk int) (SeaEvent, bool) {
+	eligible := []SeaEvent{}
+	total := 0.0
+	for _, event := range g.SeaEvents {
+		if risk < event.MinRisk || g.Wanted() < event.MinWanted {
+			continue
+		}
+		if len(event.Weather) > 0 && !contains(event.Weather, g.Weather) {
+			continue
+		}
+		eligible = append(eligible, event)
+		total += g.seaEventWeight(event)
+	}
+	if len(eligible) == 0 {
+		return SeaEvent{}, false
+	}
+	roll := rand.Float64() * total
+	for _, event := range eligible {
+		roll -= g.seaEventWeight(event)
+		if roll <= 0 {
+			return event, true
+		}
+	}
+	return eligible[len(eligible)-1], true
+}
+
+func (g *GameState) seaEventWeight(event SeaEvent) float64 {
+	if event.MinWanted > 0 {
+		return event.Weight + float64(g.Wanted())*0.5
+	}
+	return event.Weight
+}
+
+func (g *GameState) StartSeaEvent(event SeaEvent) {
+	g.AddLog(event.Name+": "+event.Text, "event")
+	if len(event.Weather) > 0 {
+		g.CrewComment("storm")
+	}
+	if event.Enemy != "" {
+		g.startSeaCombat(event.Enemy)
+		return
+	}
+	if len(event.Choices) > 0 {
+		choices := make([]string, 0, len(event.Choices))
+		for _, choice := range event.Choices {
+			label := choice.Label
+			if choice.Cost > 0 {
+				label = fmt.Sprintf("%s (%d)", choice.Label, choice.Cost)
+			}
+			choices = append(choices, label)
+		}
+		g.Encounter = &EncounterState{EventID: event.ID, Title: event.Name, Body: event.Text, Choices: choices}
+		return
+	}
+	outcome := event.Success
+	if event.Check != "" && !g.SkillCheck(event.Check) {
+		outcome = event.Failure
+	}
+	g.ApplySeaOutcome(outcome)
+}
+
+func (g *GameState) ResolveEncounter(label string) {
+	if g.Encounter == nil {
+		return
+	}
+	var event SeaEvent
+	for _, candidate := range g.SeaEvents {
+		if candidate.ID == g.Encounter.EventID {
+			event = candidate
+			break
+		}
+	}
+	g.Encounter = nil
+	for _, choice := range event.Choices {
+		if !strings.HasPrefix(label, choice.Label) {
+			continue
+		}
+		if choice.Cost > 0 {
+			if g.Money < choice.Cost {
+				g.AddLog("You can't cover the cost. The moment slips away.", "event")
+				return
+			}
+			g.Money -= choice.Cost
+		}
+		outcome := choice.Success
+		if choice.Check != "" && !g.SkillCheck(choice.Check) {
+			outcome = choice.Failure
+		}
+		g.ApplySeaOutcome(outcome)
+		return
+	}
+}
+
+func (g *GameState) ApplySeaOutcome(outcome SeaOutcome) {
+	if outcome.Text != "" {
+		g.AddLog(outcome.Text, "event")
+	}
+	g.Money = max(0, g.Money+outcome.Money)
+	g.AddWanted(outcome.Wanted)
+	for faction, delta := range outcome.Rep {
+		g.AdjustRep(faction, delta)
+	}
+	g.Morale += outcome.Morale
+	g.Player.HP = min(g.Player.MaxHP, g.Player.HP+outcome.HP)
+	if outcome.Hull < 0 {
+		g.DamageHull(-outcome.Hull)
+	}
+	for i := 0; i < outcome.Hours; i++ {
+		g.AdvanceTime()
+	}
+	if outcome.Item != "" {
+		if item, ok := g.Items[outcome.Item]; ok {
+			if g.InventorySlots()+g.slotsFor(outcome.Item) <= g.Player.MaxSlots {
+				g.Player.Inventory = append(g.Player.Inventory, outcome.Item)
+			} else {
+				g.AddLog("No room to stow the "+item.Name+". It goes over the side.", "event")
+			}
+		}
+	}
+	if outcome.Search {
+		g.SearchHold()
+	}
+	if outcome.Enemy != "" {
+		g.startSeaCombat(outcome.Enemy)
+	}
+}
+
+func (g *GameState) startSeaCombat(enemyID string) {
+	enemy, ok := g.Enemies[enemyID]
+	if !ok {
+		return
+	}
+	g.Combat = NewCombatState(enemyID, enemy)
+	g.AddLog(fmt.Sprintf("Combat begins with %s!", enemy.Name), "combat")
+}
@@ -17,6 +17,12 @@ type Player struct {
 	Charm       int
 	Wits        int
 	ActiveFruit string
+	Macros      map[string]string
+	Hunger      int
+	Fatigue     int
+	Status      map[string]int
+	Lit         string
+	Fuel        map[string]int
 }
 
 type GameState struct {
@@ -27,9 +33,28 @@ type GameState struct {
 	Quests     map[string]*Quest
 	Islands    map[string]*Island
 	Player     Player
+	Ship       Ship
 	Flags      map[string]bool
-	NPCState   map[string]string
-	Wanted     int
+	People     map[string]*NPCRecord
+	Rival      RivalState
+	Rumors     []Rumor
+	Gossiped   map[string]int
+	Puzzles    map[string]*PuzzleState
+	PuzzleDefs []Puzzle
+	Containers map[string]*ContainerState
+	Rules      []Rule
+	RoomRules  RoomRules
+	Recipes    []Recipe
+	SeaEvents  []SeaEvent
+	Learned    map[string]bool
+	Found      map[string]bool
+	Scripts    map[string]*Script
+	Events     *EventBus
+	Stats      map[string]int
+	Unlocked   map[string]bool
+	Reputation map[string]int
+	Heat       map[string]int
+	LastCrime  map[string]int
 	Morale     int
 	Money      int
 	Day        int
@@ -37,6 +62,13 @@ type GameState struct {
 	Log        []LogEntry
 	Combat     *CombatState
 	Discovered map[string]bool
+	Weather    string
+	Encounter  *EncounterState
+	Companions []string
+	Stock      map[string]map[string]int
+	Market     map[string]map[string]float64
+	Haggled    map[string]float64
+	Refused    bool
 }
 
 type LogEntry struct {
@@ -54,24 +86,55 @@ func NewGameState() *GameState {
 		Enemies:    enemies,
 		Quests:     quests,
 		Islands:    islands,
-		Player:     Player{Location: "ship_deck", Inventory: []string{}, Equipped: map[string]string{"weapon": "", "charm": "", "tool": ""}, MaxSlots: 12, HP: 24, MaxHP: 24, Grit: 2, Charm: 2, Wits: 2},
+		Player:     Player{Location: "ship_deck", Inventory: []string{}, Equipped: map[string]string{"weapon": "", "charm": "", "tool": "", "disguise": ""}, MaxSlots: 12, HP: 24, MaxHP: 24, Grit: 2, Charm: 2, Wits: 2, Macros: map[string]string{}, Status: map[string]int{}, Fuel: map[string]int{}},
+		Ship:       NewShip(),
 		Flags:      map[string]bool{},
-		NPCState:   map[string]string{},
-		Wanted:     0,
+		Reputation: map[string]int{},
+		Heat:       map[string]int{},
+		LastCrime:  map[string]int{},
 		Morale:     0,
 		Money:      80,
 		Day:        1,
 		TimeOfDay:  9,
 		Log:        []LogEntry{},
 		Discovered: map[string]bool{},
-	}
+		Weather:    "clear",
+		Companions: []string{},
+		Rival:      NewRival(),
+		Rumors:     []Rumor{},
+		Gossiped:   map[string]int{},
+		Puzzles:    map[string]*PuzzleState{},
+		Events:     NewEventBus(),
+		Stats:      map[string]int{},
+		Unlocked:   map[string]bool{},
+		Found:      map[string]bool{},
+	}
+	var errs [5]error
+	state.Rules, errs[0] = LoadRules()
+	state.RoomRules, errs[1] = LoadRoomRules()
+	state.Recipes, errs[2] = LoadRecipes()
+	state.PuzzleDefs, errs[3] = LoadPuzzles()
+	state.SeaEvents, errs[4] = LoadSeaEvents()
+	state.subscribeCore()
 	state.MarkDiscovered("ship_deck")
 	state.MarkDiscovered("ship_cabin")
 	state.AddLog("You are a rookie captain chasing legendary treasure across the Wild Current.", "story")
 	state.AddLog("Try LOOK, INVENTORY, and GO NORTH to begin.", "hint")
-	for id, npc := range state.NPCs {
-		state.NPCState[id] = npc.Disposition
+	state.InitNPCs()
+	state.InitContainers()
+	state.InitRecipes()
+	scripts, problems := LoadScripts()
+	state.Scripts = scripts
+	for _, problem := range problems {
+		state.AddLog(problem, "system")
+	}
+	for _, err := range errs {
+		if err != nil {
+			state.AddLog("Content "+err.Error(), "system")
+		}
 	}
+	state.PlaceRival()
+	state.InitEconomy()
 	return state
 }
 
@@ -79,6 +142,11 @@ func (g *GameState) AddLog(text string, kind string) {
 	g.Log = append([]LogEntry{{Time: g.TimeStamp(), Text: text, Kind: kind}}, g.Log...)
 }
 
+func (g *GameState) refuse(message string) string {
+	g.Refused = true
+	return message
+}
+
 func (g *GameState) TimeStamp() string {
 	return fmt.Sprintf("Day %d %02d:00", g.Day, g.TimeOfDay)
 }
@@ -88,7 +156,16 @@ func (g *GameState) AdvanceTime() {
 	if g.TimeOfDay >= 24 {
 		g.Day++
 		g.TimeOfDay = 0
+		g.Emit(DayStarted, "", "")
 	}
+	g.TickNeeds()
+	g.TickLight()
+	g.FollowSchedules()
+	g.RivalTick()
+	if g.TimeOfDay%6 == 0 {
+		g.RollWeather()
+	}
+	g.CheckCrewMorale()
 }
 
 func (g *GameState) Room() *Room {
@@ -110,16 +187,22 @@ func (g *GameState) InventorySlots() int {
 	for _, itemID := range g.Player.Inventory {
 		count += g.Items[itemID].Slots
 	}
+	for _, itemID := range g.carriedContainers() {
+		count += g.containerUsed(itemID)
+	}
 	return count
 }
 
-func (g *GameState) HasItem(id string) bool {
-	for _, itemID := range g.Player.Inventory {
-		if itemID == id {
-			return true
+func (g *GameState) checkEquipped() {
+	for slot, itemID := range g.Player.Equipped {
+		if itemID != "" && !contains(g.Player.Inventory, itemID) {
+			g.Player.Equipped[slot] = ""
 		}
 	}
-	return false
+}
+
+func (g *GameState) HasItem(id string) bool {
+	return contains(g.Carried(), id)
 }
 
 func (g *GameState) FindItem(name string, list []string) string {
@@ -164,18 +247,30 @@ func (g *GameState) Move(direction string) string {
 	if !ok {
 		return "You can't go that way."
 	}
-	if err := g.CanEnter(dest); err != "" {
-		return err
+	if refusal := g.ExitRefusal(direction); refusal != "" {
+		return refusal
 	}
 	g.Player.Location = dest
 	g.MarkDiscovered(dest)
 	g.AdvanceTime()
-	g.MaybePatrol()
+	if next := g.Rooms[dest]; next != nil && next.Island != room.Island && room.Island != "Ship" && next.Island != "Ship" {
+		g.Voyage(room.Island, next.Island)
+	}
+	if contains(g.Room().Tags, "checkpoint") {
+		g.Checkpoint()
+	}
+	g.Emit(RoomEntered, dest, room.ID)
+	g.MaybeAmbush()
+	g.NPCReactions()
+	g.MeetRival()
+	if message := g.RunHook("rooms/"+dest, "on_enter", map[string]interface{}{"room": dest}); message != "" {
+		return g.Look() + "\n" + message
+	}
 	return g.Look()
 }
 
 func (g *GameState) MaybePatrol() {
-	if g.Wanted < 3 {
+	if g.Wanted() < 3 {
 		return
 	}
 	room := g.Room()
@@ -188,35 +283,15 @@ func (g *GameState) MaybePatrol() {
 	}
 }
 
-func (g *GameState) CanEnter(dest string) string {
-	if dest == "navy_outpost" && !g.Flags["bribed"] {
-		return "The Bluecoat officer blocks the way. A donation might help."
-	}
-	if dest == "ruins_hall" && !g.Flags["ruinUnlocked"] {
-		return "The stone gate is locked."
-	}
-	if dest == "ruins_core" && !g.Flags["innerUnlocked"] {
-		return "A sealed door bars the way. The sea must hear your call."
-	}
-	if dest == "reef_shallows" && g.Player.ActiveFruit != "" {
-		g.Flags["drowned"] = true
-		return "The cursed power drags you under the waves. The sea refuses you."
-	}
-	if dest == "sky_shrine" && g.Player.ActiveFruit == "stone_fruit" {
-		return "The stone curse makes the storm lift impossible. You're too heavy."
-	}
-	if g.Wanted >= 5 && dest == "navy_outpost" {
-		return "Bluecoat Navy seals the outpost. You're turned away."
-	}
-	return ""
-}
-
 func (g *GameState) Look() string {
 	room := g.Room()
 	if room == nil {
 		return "You see nothing but mist."
 	}
-	lines := []string{fmt.Sprintf("%s - %s", room.Name, room.Island), room.Desc}
+	if g.InDarkness() {
+		return g.DarkLook(room)
+	}
+	lines := []string{fmt.Sprintf("%s - %s", room.Name, room.Island), g.RoomDesc(room)}
 	if len(room.Items) > 0 {
 		lines = append(lines, "You see: "+g.ListItemNames(room.Items))
 	}
@@ -226,20 +301,28 @@ func (g *GameState) Look() string {
 	if len(room.Enemies) > 0 {
 		lines = append(lines, "Threats: "+g.ListEnemyNames(room.Enemies))
 	}
-	lines = append(lines, "Exits: "+strings.Join(exitKeys(room.Exits), ", "))
+	lines = append(lines, g.PuzzleLook()...)
+	lines = append(lines, "Exits: "+strings.Join(g.exitLabels(room), ", "))
 	return strings.Join(lines, "\n")
 }
 
 func (g *GameState) ListItemNames(ids []string) string {
 	names := make([]string, 0, len(ids))
-	for _, id := range ids {
-		if item, ok := g.Items[id]; ok {
-			names = append(names, item.Name)
+	for _, id := range stackIDs(ids) {
+		if _, ok := g.Items[id]; ok {
+			names = append(names, g.stackName(id, countID(ids, id)))
 		}
 	}
 	return strings.Join(names, ", ")
 }
 
+func (g *GameState) stackName(itemID string, count int) string {
+	if count > 1 {
+		return fmt.Sprintf("%s x%d", g.Items[itemID].Name, count)
+	}
+	return g.Items[itemID].Name
+}
+
 func (g *GameState) ListNPCNames(ids []string) string {
 	names := make([]string, 0, len(ids))
 	for _, id := range ids {
@@ -272,6 +355,9 @@ func (g *GameState) Examine(name string) string {
 	if itemID := g.FindItem(name, g.Player.Inventory); itemID != "" {
 		return g.Items[itemID].Desc
 	}
+	if g.InDarkness() {
+		return g.refuse("It's too dark to make out any detail.")
+	}
 	room := g.Room()
 	if itemID := g.FindItem(name, room.Items); itemID != "" {
 		return g.Items[itemID].Desc
@@ -282,55 +368,145 @@ func (g *GameState) Examine(name string) string {
 	if enemyID := g.FindEnemy(name, room.Enemies); enemyID != "" {
 		return g.Enemies[enemyID].Desc
 	}
-	return "You find nothing like that to examine."
+	return g.refuse("You find nothing like that to examine.")
 }
 
 func (g *GameState) Take(name string) string {
+	return g.TakeCount(name, 1)
+}
+
+func (g *GameState) TakeCount(name string, count int) string {
 	room := g.Room()
 	itemID := g.FindItem(name, room.Items)
 	if itemID == "" {
-		return "You don't see that here."
+		return g.refuse("You don't see that here.")
 	}
 	item := g.Items[itemID]
-	if g.InventorySlots()+item.Slots > g.Player.MaxSlots {
-		return "You're carrying too much already."
+	if Containers()[itemID].Fixed {
+		return g.refuse(fmt.Sprintf("The %s won't budge.", item.Name))
+	}
+	if g.InDarkness() && !g.SkillCheck("wits") {
+		return g.refuse("You grope around in the dark but can't lay a hand on it.")
 	}
-	room.Items = removeID(room.Items, itemID)
-	g.Player.Inventory = append(g.Player.Inventory, itemID)
-	if item.Contraband {
-		g.Wanted++
+	taken := 0
+	for taken < count && contains(room.Items, itemID) {
+		if g.InventorySlots()+g.slotsFor(itemID) > g.Player.MaxSlots {
+			break
+		}
+		room.Items = removeOne(room.Items, itemID)
+		g.Player.Inventory = append(g.Player.Inventory, itemID)
+		g.Emit(ItemTaken, itemID, room.ID)
+		taken++
+	}
+	switch {
+	case taken == 0:
+		return g.refuse("You're carrying too much already.")
+	case taken == 1 && count == 1:
+		return fmt.Sprintf("You take the %s.", item.Name)
+	case taken < count && contains(room.Items, itemID):
+		return g.refuse(fmt.Sprintf("You take %s. You can't carry any more.", g.stackName(itemID, taken)))
+	}
+	return fmt.Sprintf("You take %s.", g.stackName(itemID, taken))
+}
+
+func (g *GameState) TakeAll(except []string) string {
+	lines := []string{}
+	for _, itemID := range stackIDs(g.Room().Items) {
+		if g.excluded(itemID, except) || Containers()[itemID].Fixed {
+			continue
+		}
+		lines = append(lines, g.TakeCount(itemID, countID(g.Room().Items, itemID)))
 	}
-	return fmt.Sprintf("You take the %s.", item.Name)
+	if len(lines) == 0 {
+		return g.refuse("There's nothing here to take.")
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (g *GameState) Drop(name string) string {
+	return g.DropCount(name, 1)
+}
+
+func (g *GameState) DropCount(name string, count int) string {
 	itemID := g.FindItem(name, g.Player.Inventory)
 	if itemID == "" {
-		return "You don't have that."
+		return g.refuse("You don't have that.")
 	}
-	g.Player.Inventory = removeID(g.Player.Inventory, itemID)
+	count = min(count, countID(g.Player.Inventory, itemID))
 	room := g.Room()
-	room.Items = append(room.Items, itemID)
-	return fmt.Sprintf("You drop the %s.", g.Items[itemID].Name)
+	for i := 0; i < count; i++ {
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+		room.Items = append(room.Items, itemID)
+	}
+	if count == 1 && g.notEmpty(itemID) != "" {
+		return fmt.Sprintf("You drop the %s, contents and all.", g.Items[itemID].Name)
+	}
+	if count == 1 {
+		return fmt.Sprintf("You drop the %s.", g.Items[itemID].Name)
+	}
+	return fmt.Sprintf("You drop %s.", g.stackName(itemID, count))
+}
+
+func (g *GameState) DropAll(except []string) string {
+	lines := []string{}
+	for _, itemID := range stackIDs(g.Player.Inventory) {
+		if g.excluded(itemID, except) {
+			continue
+		}
+		lines = append(lines, g.DropCount(itemID, countID(g.Player.Inventory, itemID)))
+	}
+	if len(lines) == 0 {
+		return g.refuse("You have nothing to drop.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *GameState) excluded(itemID string, except []string) bool {
+	for _, phrase := range except {
+		if len(g.MatchNames(phrase, []string{itemID})) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func (g *GameState) Talk(name string) string {
 	room := g.Room()
 	npcID := g.FindNPC(name, room.NPCs)
 	if npcID == "" {
-		return "No one like that is here."
+		return g.refuse("No one like that is here.")
+	}
+	if rule, ok := g.RunRules("talk", "", name); ok {
+		return rule.Message
 	}
-	if g.NPCState[npcID] == "hostile" {
-		return "They glare and refuse to speak."
+	mood := g.Mood(npcID)
+	if mood == "hostile" {
+		return g.refuse("They glare and refuse to speak.")
 	}
 	npc := g.NPCs[npcID]
+	guarded := g.Wanted() >= 4 && npc.Disposition == "hostile"
+	reply := ""
+	if !guarded {
+		reply = g.RunHook("npcs/"+npcID, "on_talk", map[string]interface{}{"npc": npcID, "mood": mood})
+	}
+	if reply != "" {
+		g.person(npcID).Met = true
+		return reply
+	}
 	response := npc.Talk
-	if g.Wanted >= 4 && npc.Disposition == "hostile" {
+	if mood == "friendly" && npc.TalkFriendly != "" {
+		response = npc.TalkFriendly
+	}
+	if npc.Faction != "" && g.Reputation[npc.Faction] <= -2 && npc.TalkWary != "" {
+		response = npc.TalkWary
+	}
+	if guarded {
 		response = "The Bluecoat glowers. 'Hands where I can see them.'"
 	}
-	if g.NPCState[npcID] == "neutral" {
-		g.NPCState[npcID] = "met"
+	if line := g.memoryLine(npcID); line != "" {
+		response += " " + line
 	}
+	g.person(npcID).Met = true
 	return response
 }
 
@@ -338,15 +514,20 @@ func (g *GameState) Bribe(name string) string {
 	room := g.Room()
 	npcID := g.FindNPC(name, room.NPCs)
 	if npcID == "" {
-		return "There's no one here to bribe."
+		return g.refuse("There's no one here to bribe.")
 	}
 	if g.Money < 25 {
-		return "You don't have enough coin to bribe convincingly."
+		return g.refuse("You don't have enough coin to bribe convincingly.")
 	}
 	g.Money -= 25
-	g.Wanted = max(0, g.Wanted-1)
-	g.Flags["bribed"] = true
-	g.NPCState[npcID] = "friendly"
+	if faction := g.NPCs[npcID].Faction; faction != "" && faction != "navy" {
+		g.AdjustRep(faction, 1)
+	} else {
+		g.AddWanted(-1)
+	}
+	g.SetFlag("bribed")
+	g.SetMood(npcID, "friendly")
+	g.Remember(npcID, "bribed")
 	return "The bribe slips into a pocket. The way is suddenly less guarded."
 }
 
@@ -354,180 +535,101 @@ func (g *GameState) Threaten(name string) string {
 	room := g.Room()
 	npcID := g.FindNPC(name, room.NPCs)
 	if npcID == "" {
-		return "No one here looks threatened."
+		return g.refuse("No one here looks threatened.")
 	}
 	check := g.SkillCheck("grit")
+	g.AdjustRep(g.NPCs[npcID].Faction, -1)
+	g.Remember(npcID, "threatened")
 	if check {
-		g.Wanted++
-		g.NPCState[npcID] = "hostile"
+		g.AddWanted(1)
+		g.SetMood(npcID, "hostile")
 		return "Your threat lands. People scatter and the wanted posters multiply."
 	}
-	g.Wanted++
-	g.NPCState[npcID] = "hostile"
+	g.AddWanted(1)
+	g.SetMood(npcID, "hostile")
 	return "Your threat falls flat. Someone laughs."
 }
 
 func (g *GameState) Use(itemName string, target string) string {
 	itemID := g.FindItem(itemName, g.Player.Inventory)
 	if itemID == "" {
-		return "You don't have that to use."
+		return g.refuse("You don't have that to use.")
 	}
 	item := g.Items[itemID]
+	if item.Type == "upgrade" {
+		return g.InstallUpgrade(itemID)
+	}
+	if _, ok := Disguises()[itemID]; ok {
+		return g.Wear(item.Name)
+	}
 	if item.Fruit {
 		if g.Player.ActiveFruit != "" {
-			return "Only one cursed fruit at a time. The sea insists."
+			return g.refuse("Only one cursed fruit at a time. The sea insists.")
 		}
 		g.Player.ActiveFruit = itemID
-		g.Player.Inventory = removeID(g.Player.Inventory, itemID)
+		g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
 		g.Morale++
 		return "Power surges through you. The sea now resents you."
 	}
-	switch itemID {
-	case "cipher_lens":
-		if g.Player.Location != "mist_library" {
-			return "The lens needs a quiet library to read the glyphs."
+	if _, ok := Lights()[itemID]; ok && target == "" {
+		if g.Player.Lit != itemID {
+			return g.LightUp(itemID)
 		}
-		if g.HasItem("glyph_frag_1") && g.HasItem("glyph_frag_2") && g.HasItem("glyph_frag_3") {
-			g.Flags["coordsDecoded"] = true
-			g.Player.Inventory = append(g.Player.Inventory, "treasure_core")
-			return "The lens reveals the Treasure Coordinate Core within the fragments."
+		if message := g.UsePuzzleItem(itemID); message != "" {
+			return message
 		}
-		return "The lens reveals hints, but you need all fragments."
-	case "stone_key":
-		if g.Player.Location == "ruins_gate" {
-			g.Flags["ruinUnlocked"] = true
-			return "The stone key turns. The gate groans open."
-		}
-	case "storm_lantern":
-		if g.Player.Location == "ruins_hall" {
-			g.Flags["innerUnlocked"] = true
-			return "The lantern's glow wakes hidden runes. The inner door opens."
-		}
-	case "gadget_gull":
-		g.Morale++
-		return "The gull chirps. Your crew laughs. Morale rises."
+		return g.Douse(itemID)
+	}
+	if message := g.UsePuzzleItem(itemID); message != "" {
+		return message
+	}
+	if message := g.RunHook("items/"+itemID, "on_use", map[string]interface{}{"item": itemID, "target": target}); message != "" {
+		return message
+	}
+	if rule, ok := g.RunRules("use", itemID, target); ok {
+		return rule.Message
+	}
+	if _, ok := Consumables()[itemID]; ok && (target == "" || target == "self" || target == "me") {
+		return g.Consume(itemID)
+	}
+	switch itemID {
+	case "lamp_oil":
+		return g.Refuel(itemID)
 	case "rum":
-		if target == "broker" {
-			g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-			g.Player.Inventory = append(g.Player.Inventory, "stone_key")
-			if quest, ok := g.Quests["broker"]; ok {
-				quest.Done = true
-				quest.Outcome = "The broker traded a stone key."
-			}
-			return "The broker trades the rum for a stone key."
-		}
-		g.Morale++
-		return "You take a sip. Courage bubbles up."
-	case "medkit":
-		if target == "dockhand" {
-			g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-			g.Player.Inventory = append(g.Player.Inventory, "sun_coin")
-			if quest, ok := g.Quests["dockhand"]; ok {
-				quest.Done = true
-				quest.Outcome = "The dockhand repaid your kindness."
-			}
-			return "You patch the dockhand. They slip you a sun coin."
-		}
-		g.Player.HP = min(g.Player.MaxHP, g.Player.HP+6)
-		return "You patch yourself up."
-	case "sun_coin":
-		if target == "shrine" || g.Player.Location == "sky_shrine" {
-			g.Morale += 2
-			g.Flags["shrineBlessing"] = true
-			if quest, ok := g.Quests["priest"]; ok {
-				quest.Done = true
-				quest.Outcome = "The shrine accepted your offering."
-			}
-			return "The shrine hums. The storm calms for now."
-		}
-	case "bribe":
-		if target == "bluecoat officer" || target == "officer" {
-			g.Flags["bribed"] = true
-			g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-			return "The officer pockets the coins and steps aside."
-		}
-	case "spice":
-		if target == "gadgeteer" {
-			g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-			g.Player.Inventory = append(g.Player.Inventory, "cipher_lens")
-			if quest, ok := g.Quests["gadgeteer"]; ok {
-				quest.Done = true
-				quest.Outcome = "Spice traded for a cipher lens."
-			}
-			return "The gadgeteer trades a cipher lens for the spice."
+		if target != "" {
+			return g.RumorForRum(target)
 		}
 	case "repair_kit":
-		if target == "shipwright" {
-			g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-			g.Player.Inventory = append(g.Player.Inventory, "dock_pass")
-			g.Morale++
-			if quest, ok := g.Quests["shipwright"]; ok {
-				quest.Done = true
-				quest.Outcome = "The shipwright granted you a dock pass."
+		if target == "ship" || target == "hull" || g.Room().Island == "Ship" {
+			if g.Ship.Hull >= g.Ship.MaxHull {
+				return g.refuse("The hull is already sound.")
 			}
-			return "The shipwright hands you a dock pass."
+			g.Player.Inventory = removeOne(g.Player.Inventory, itemID)
+			return fmt.Sprintf("You patch the hull. (+%d hull)", g.RepairHull(10))
 		}
-	case "treasure_core":
-		if g.Player.Location == "ship_deck" {
-			g.Flags["treasureEscaped"] = true
-			return "You set the coordinates and cut the sails."
+	case "bounty_poster":
+		if g.FindNPC(target, g.Room().NPCs) == "officer" {
+			return g.PayBounty()
 		}
 	}
-	return "Nothing happens."
+	if target != "" && g.FindItem(target, g.Player.Inventory) != "" {
+		return g.Combine(itemID, target)
+	}
+	return g.refuse("Nothing happens.")
 }
 
 func (g *GameState) Attack(name string) string {
 	room := g.Room()
 	enemyID := g.FindEnemy(name, room.Enemies)
 	if enemyID == "" {
-		return "No enemy by that name is here."
+		return g.refuse("No enemy by that name is here.")
 	}
 	g.Combat = NewCombatState(enemyID, g.Enemies[enemyID])
 	return fmt.Sprintf("Combat begins with %s!", g.Enemies[enemyID].Name)
 }
 
-func (g *GameState) Buy(itemName string) string {
-	room := g.Room()
-	if !contains(room.Tags, "shop") {
-		return "There's nothing for sale here."
-	}
-	itemID := g.FindItem(itemName, room.Items)
-	if itemID == "" {
-		return "That item isn't for sale here."
-	}
-	item := g.Items[itemID]
-	price := g.Price(item.Value)
-	if g.Money < price {
-		return "You can't afford that."
-	}
-	if g.InventorySlots()+item.Slots > g.Player.MaxSlots {
-		return "You're carrying too much already."
-	}
-	g.Money -= price
-	room.Items = removeID(room.Items, itemID)
-	g.Player.Inventory = append(g.Player.Inventory, itemID)
-	return fmt.Sprintf("You buy %s for %d coins.", item.Name, price)
-}
-
-func (g *GameState) Sell(itemName string) string {
-	room := g.Room()
-	if !contains(room.Tags, "shop") {
-		return "No one is buying here."
-	}
-	itemID := g.FindItem(itemName, g.Player.Inventory)
-	if itemID == "" {
-		return "You don't have that to sell."
-	}
-	item := g.Items[itemID]
-	sale := g.Price(item.Value / 2)
-	g.Money += sale
-	g.Player.Inventory = removeID(g.Player.Inventory, itemID)
-	room.Items = append(room.Items, itemID)
-	return fmt.Sprintf("You sell %s for %d coins.", item.Name, sale)
-}
-
-func (g *GameState) Price(base int) int {
-	mod := 1.0 + float64(g.Wanted)*0.05
+func (g *GameState) Price(base int, faction string) int {
+	mod := 1.0 + float64(g.Wanted())*0.05 - g.repDiscount(faction)
 	if g.Morale >= 3 {
 		mod -= 0.1
 	}
@@ -546,21 +648,16 @@ func (g *GameState) SkillCheck(stat string) bool {
 	case "wits":
 		statBonus = g.Player.Wits
 	}
+	statBonus += g.CrewSkillBonus(stat) + g.StatusBonus(stat) - g.NeedsPenalty()
 	return roll+statBonus+bonus >= 12
 }
 
 func (g *GameState) ResolveQuests() {
 	if g.HasItem("glyph_frag_1") && g.HasItem("glyph_frag_2") && g.HasItem("glyph_frag_3") {
-		if quest, ok := g.Quests["main"]; ok {
-			quest.Done = true
-			quest.Outcome = "Fragments secured. Decode them with a cipher lens."
-		}
+		g.CompleteQuest("main", "Fragments secured. Decode them with a cipher lens.")
 	}
 	if g.Flags["ruinUnlocked"] {
-		if quest, ok := g.Quests["broker"]; ok {
-			quest.Done = true
-			quest.Outcome = "Key delivered."
-		}
+		g.CompleteQuest("broker", "Key delivered.")
 	}
 }
 
@@ -568,10 +665,13 @@ func (g *GameState) EndingsCheck() (bool, string) {
 	if g.Player.HP <= 0 {
 		return true, "You slump to the ground. The Bluecoat Navy captures you."
 	}
+	if g.Ship.Hull <= 0 {
+		return true, "Your ship splinters beneath you and the Wild Current swallows the wreck."
+	}
 	if g.Flags["drowned"] {
 		return true, "The sea claims you for daring its curse."
 	}
-	if g.Wanted >= 7 {
+	if g.Heat[g.HeatIsland()] >= 7 {
 		return true, "Bluecoat Navy corners you. Chains clamp shut."
 	}
 	if g.Flags["treasureEscaped"] {
@@ -581,7 +681,7 @@ func (g *GameState) EndingsCheck() (bool, string) {
 		return true, "You vanish into the Wild Current with the treasure."
 	}
 	if g.Flags["treasureLost"] {
-		return true, "The rival pirate steals the treasure core. Your legend ends in a whimper."
+		return true, "Your rival reaches the Glyph Core first and sails off with the treasure. Your legend ends in a whimper."
 	}
 	return false, ""
 }
@@ -596,6 +696,36 @@ func removeID(list []string, id string) []string {
 	return result
 }
 
+func removeOne(list []string, id string) []string {
+	for i, entry := range list {
+		if entry == id {
+			result := append([]string{}, list[:i]...)
+			return append(result, list[i+1:]...)
+		}
+	}
+	return list
+}
+
+func countID(list []string, id string) int {
+	count := 0
+	for _, entry := range list {
+		if entry == id {
+			count++
+		}
+	}
+	return count
+}
+
+func stackIDs(list []string) []string {
+	ids := []string{}
+	for _, entry := range list {
+		if !contains(ids, entry) {
+			ids = append(ids, entry)
+		}
+	}
+	return ids
+}
+
 func contains(list []string, target string) bool {
 	for _, entry := range list {
 		if entry == target {